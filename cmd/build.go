@@ -0,0 +1,668 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/spf13/cobra"
+)
+
+// pureBackends 는 pigo 가 대신 빌드할 수 있는 build backend 와, 빌드 설정이 있으면
+// backend 를 직접 써야 하는 [tool.*] 테이블입니다.
+var pureBackends = map[string]string{
+	"hatchling.build":         "hatch.build",
+	"flit_core.buildapi":      "flit",
+	"pdm.backend":             "pdm.build",
+	"poetry.core.masonry.api": "poetry",
+	"setuptools.build_meta":   "setuptools",
+	"uv_build":                "uv.build-backend",
+}
+
+// extensionSources 는 컴파일이 필요한 소스이거나 이미 컴파일된 확장 모듈입니다.
+var extensionSources = map[string]bool{
+	".c": true, ".cc": true, ".cpp": true, ".cxx": true, ".pyx": true, ".rs": true,
+	".f90": true, ".so": true, ".pyd": true, ".dylib": true,
+}
+
+// defaultLicenseFiles 는 license-files 가 없을 때 wheel 에 넣는 파일입니다. (setuptools 와 같음)
+var defaultLicenseFiles = []string{"LICEN[CS]E*", "COPYING*", "NOTICE*", "AUTHORS*"}
+
+type buildOptions struct {
+	OutDir     string
+	Wheel      bool
+	Sdist      bool
+	UseBackend bool
+}
+
+// buildFile 은 wheel 이나 sdist 에 넣을 파일 하나입니다.
+type buildFile struct {
+	Path string // 디스크 경로
+	Name string // 압축 파일 안의 경로 (/ 구분)
+}
+
+// nativeBuild 는 pigo 가 build backend 없이 만드는 순수 python 배포판입니다.
+type nativeBuild struct {
+	root        string
+	meta        *dist.Metadata
+	entryPoints []byte
+	packages    []buildFile
+	licenses    []buildFile // Name 은 프로젝트 루트 기준 경로
+}
+
+// runBuild 는 현재 프로젝트의 sdist 와 wheel 을 outDir 에 만듭니다.
+func runBuild(opts buildOptions) error {
+	pp, err := modfile.ReadPyproject(projectPath(modfile.PyprojectFileName))
+	if err != nil {
+		return err
+	}
+	outDir := opts.OutDir
+	if outDir == "" {
+		outDir = projectPath("dist")
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	var nb *nativeBuild
+	reason := "--use-backend"
+	if !opts.UseBackend {
+		if nb, reason, err = planNativeBuild(pp); err != nil {
+			return err
+		}
+	}
+
+	var built []string
+	if nb != nil {
+		if opts.Sdist {
+			path, err := nb.writeSdist(outDir)
+			if err != nil {
+				return err
+			}
+			built = append(built, path)
+		}
+		if opts.Wheel {
+			path, err := nb.writeWheel(outDir)
+			if err != nil {
+				return err
+			}
+			built = append(built, path)
+		}
+	} else {
+		bs := buildSystem(pp)
+		fmt.Fprintf(os.Stderr, "Building with %s (%s)\n", bs.BuildBackend, reason)
+		if built, err = buildWithBackend(bs, outDir, opts); err != nil {
+			return err
+		}
+	}
+	for _, path := range built {
+		fmt.Printf("Built %s\n", displayPath(path))
+	}
+	return nil
+}
+
+// planNativeBuild 는 pigo 가 직접 빌드할 수 있으면 nativeBuild 를 돌려줍니다.
+// 그럴 수 없으면 nil 과 함께 build backend 를 써야 하는 이유를 돌려줍니다.
+func planNativeBuild(pp *modfile.Pyproject) (*nativeBuild, string, error) {
+	root := projectRoot()
+	_, err := os.Stat(filepath.Join(root, "setup.py"))
+	hasSetupPy := err == nil
+
+	p := pp.Project
+	if p == nil {
+		if pp.BuildSystem == nil && !hasSetupPy {
+			return nil, "", fmt.Errorf("%s has no [project] table (name and version are required)", modfile.PyprojectFileName)
+		}
+		return nil, "no [project] table", nil
+	}
+	if hasSetupPy {
+		return nil, "setup.py", nil
+	}
+	if pp.BuildSystem != nil && pp.BuildSystem.BuildBackend != "" {
+		backend := pp.BuildSystem.BuildBackend
+		tool, ok := pureBackends[backend]
+		if !ok {
+			return nil, "build backend " + backend, nil
+		}
+		if pp.HasTool(tool) {
+			return nil, "[tool." + tool + "] settings", nil
+		}
+	}
+	if len(p.Dynamic) > 0 {
+		return nil, "dynamic " + strings.Join(p.Dynamic, ", "), nil
+	}
+	if p.Name == "" || p.Version == "" {
+		return nil, "", fmt.Errorf("%s: [project] name and version are required", modfile.PyprojectFileName)
+	}
+
+	ignore := readIgnoreRules(root)
+	packages, err := collectPackageFiles(root, p.Name, ignore)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(packages) == 0 {
+		return nil, "no python package found", nil
+	}
+	for _, f := range packages {
+		if extensionSources[strings.ToLower(filepath.Ext(f.Name))] {
+			return nil, "extension module " + displayPath(f.Path), nil
+		}
+	}
+
+	meta, licenses, err := projectMetadata(p, root)
+	if err != nil {
+		return nil, "", err
+	}
+	return &nativeBuild{
+		root:        root,
+		meta:        meta,
+		entryPoints: formatEntryPoints(p),
+		packages:    packages,
+		licenses:    licenses,
+	}, "", nil
+}
+
+// importName 은 배포판 이름을 import 이름과 파일 이름에 쓰는 형태로 바꿉니다. (my-pkg -> my_pkg)
+func importName(name string) string {
+	return strings.ReplaceAll(dist.NormalizeName(name), "-", "_")
+}
+
+// collectPackageFiles 는 wheel 에 넣을 파일을 찾습니다. name 과 같은 패키지나 모듈이
+// (src/ 레이아웃 포함) 있으면 그것만, 없으면 루트의 모든 최상위 패키지와 모듈을 넣습니다.
+func collectPackageFiles(root, name string, ignore *ignoreRules) ([]buildFile, error) {
+	base := root
+	if info, err := os.Stat(filepath.Join(root, "src")); err == nil && info.IsDir() {
+		base = filepath.Join(root, "src")
+	}
+	var tops []string
+	imp := importName(name)
+	if info, err := os.Stat(filepath.Join(base, imp)); err == nil && info.IsDir() {
+		tops = []string{imp}
+	} else if _, err := os.Stat(filepath.Join(base, imp+".py")); err == nil {
+		tops = []string{imp + ".py"}
+	} else {
+		for _, n := range dist.LocalImportNames(root) {
+			if _, err := os.Stat(filepath.Join(base, n+".py")); err == nil {
+				n += ".py"
+			}
+			tops = append(tops, n)
+		}
+	}
+
+	var files []buildFile
+	for _, top := range tops {
+		err := filepath.WalkDir(filepath.Join(base, top), func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+			if d.IsDir() {
+				if d.Name() == "__pycache__" || ignore.match(rel, true) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ext := filepath.Ext(path); ext == ".pyc" || ext == ".pyo" || ignore.match(rel, false) {
+				return nil
+			}
+			name, _ := filepath.Rel(base, path)
+			files = append(files, buildFile{Path: path, Name: filepath.ToSlash(name)})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// projectMetadata 는 [project] 테이블로 core metadata 를 만듭니다. requires-python 이 없으면
+// pigo.mod 의 python 버전을 씁니다. dependencies 가 없는데 requirements.txt 에 항목이 있으면 실패합니다.
+// 두 번째 값은 wheel 의 .dist-info/licenses/ 에 넣을 파일입니다.
+func projectMetadata(p *modfile.Project, root string) (*dist.Metadata, []buildFile, error) {
+	version, ok := dist.ParseVersion(p.Version)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: invalid version %q", modfile.PyprojectFileName, p.Version)
+	}
+	meta := &dist.Metadata{
+		Name:           p.Name,
+		Version:        version.String(),
+		Summary:        p.Description,
+		RequiresPython: p.RequiresPython,
+		Keywords:       p.Keywords,
+		Classifiers:    p.Classifiers,
+	}
+
+	if p.Readme != nil {
+		meta.Description, meta.DescriptionContentType = p.Readme.Text, p.Readme.ContentType
+		if p.Readme.File != "" {
+			data, err := os.ReadFile(filepath.Join(root, p.Readme.File))
+			if err != nil {
+				return nil, nil, fmt.Errorf("readme: %w", err)
+			}
+			meta.Description = string(data)
+			if meta.DescriptionContentType == "" {
+				switch strings.ToLower(filepath.Ext(p.Readme.File)) {
+				case ".md", ".markdown":
+					meta.DescriptionContentType = "text/markdown"
+				case ".rst":
+					meta.DescriptionContentType = "text/x-rst"
+				default:
+					meta.DescriptionContentType = "text/plain"
+				}
+			}
+		}
+	}
+
+	licensePatterns := p.LicenseFiles
+	if p.License != nil {
+		meta.LicenseExpression, meta.License = p.License.Expression, p.License.Text
+		if p.License.File != "" {
+			data, err := os.ReadFile(filepath.Join(root, p.License.File))
+			if err != nil {
+				return nil, nil, fmt.Errorf("license: %w", err)
+			}
+			meta.License = string(data)
+		}
+	}
+	if licensePatterns == nil && (p.License == nil || p.License.File == "") {
+		licensePatterns = defaultLicenseFiles
+	}
+	var licenses []buildFile
+	for _, pattern := range licensePatterns {
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("license-files: %w", err)
+		}
+		for _, m := range matches {
+			if info, err := os.Stat(m); err != nil || info.IsDir() {
+				continue
+			}
+			rel, _ := filepath.Rel(root, m)
+			licenses = append(licenses, buildFile{Path: m, Name: filepath.ToSlash(rel)})
+			meta.LicenseFiles = append(meta.LicenseFiles, filepath.ToSlash(rel))
+		}
+	}
+
+	meta.Author, meta.AuthorEmail = formatPeople(p.Authors)
+	meta.Maintainer, meta.MaintainerEmail = formatPeople(p.Maintainers)
+	for _, label := range sortedKeys(p.URLs) {
+		meta.ProjectURLs = append(meta.ProjectURLs, label+", "+p.URLs[label])
+	}
+
+	if p.RequiresPython == "" {
+		mf, err := modfile.Read(filepath.Join(root, modfile.FileName))
+		if err != nil {
+			return nil, nil, err
+		}
+		if mf.Python != "" {
+			meta.RequiresPython = ">=" + mf.Python
+		}
+	}
+
+	if p.Dependencies == nil {
+		// requirements.txt 로 채우면 sdist 의 pyproject.toml 에는 의존성이 없으므로
+		// sdist 에서 다시 빌드한 wheel 과 달라집니다. dependencies 는 직접 적어야 합니다.
+		reqFile, err := modfile.ReadRequirements(filepath.Join(root, modfile.RequirementsFileName))
+		if err != nil {
+			return nil, nil, err
+		}
+		if reqs := reqFile.List(); len(reqs) > 0 {
+			var lines []string
+			for _, req := range reqs {
+				lines = append(lines, strconv.Quote(req.String()))
+			}
+			return nil, nil, fmt.Errorf("%s: [project] has no dependencies but %s lists %d; add them to [project]:\n\n\tdependencies = [%s]",
+				modfile.PyprojectFileName, modfile.RequirementsFileName, len(reqs), strings.Join(lines, ", "))
+		}
+	}
+	meta.RequiresDist = append(meta.RequiresDist, p.Dependencies...)
+	for _, extra := range sortedKeys(p.OptionalDependencies) {
+		name := dist.NormalizeName(extra)
+		meta.ProvidesExtra = append(meta.ProvidesExtra, name)
+		for _, line := range p.OptionalDependencies[extra] {
+			req, err := dist.ParseRequirement(line)
+			if err != nil {
+				return nil, nil, fmt.Errorf("optional-dependencies.%s: %w", extra, err)
+			}
+			marker := `extra == "` + name + `"`
+			if req.Marker != "" {
+				marker = "(" + req.Marker + ") and " + marker
+			}
+			req.Marker = marker
+			meta.RequiresDist = append(meta.RequiresDist, req.String())
+		}
+	}
+	return meta, licenses, nil
+}
+
+// formatPeople 은 authors 를 Author (이름만 있는 사람) 와 Author-email 값으로 나눕니다.
+func formatPeople(people []modfile.Person) (string, string) {
+	var names, emails []string
+	for _, person := range people {
+		switch {
+		case person.Email == "":
+			names = append(names, person.Name)
+		case person.Name == "":
+			emails = append(emails, person.Email)
+		default:
+			emails = append(emails, person.Name+" <"+person.Email+">")
+		}
+	}
+	return strings.Join(names, ", "), strings.Join(emails, ", ")
+}
+
+// formatEntryPoints 는 scripts, gui-scripts, entry-points 로 entry_points.txt 를 만듭니다.
+func formatEntryPoints(p *modfile.Project) []byte {
+	groups := map[string]map[string]string{}
+	for group, eps := range p.EntryPoints {
+		groups[group] = eps
+	}
+	if len(p.Scripts) > 0 {
+		groups["console_scripts"] = p.Scripts
+	}
+	if len(p.GUIScripts) > 0 {
+		groups["gui_scripts"] = p.GUIScripts
+	}
+	var b strings.Builder
+	for _, group := range sortedKeys(groups) {
+		b.WriteString("[" + group + "]\n")
+		for _, name := range sortedKeys(groups[group]) {
+			b.WriteString(name + " = " + groups[group][name] + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	return []byte(b.String())
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// buildTime 은 압축 파일 안의 수정 시각입니다. 같은 소스는 같은 파일이 되도록
+// SOURCE_DATE_EPOCH 가 있으면 그 값을, 없으면 zip 이 표현할 수 있는 가장 이른 날을 씁니다.
+func buildTime() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// fileMode 는 실행 권한만 남긴 압축 파일 안의 권한입니다.
+func fileMode(path string) os.FileMode {
+	if info, err := os.Stat(path); err == nil && info.Mode()&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
+
+// writeWheel 은 outDir 에 <name>-<version>-py3-none-any.whl 을 만듭니다.
+func (b *nativeBuild) writeWheel(outDir string) (string, error) {
+	prefix := importName(b.meta.Name) + "-" + b.meta.Version
+	distInfo := prefix + ".dist-info"
+	path := filepath.Join(outDir, prefix+"-py3-none-any.whl")
+
+	tmp, err := os.CreateTemp(outDir, ".pigo-wheel-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := zip.NewWriter(tmp)
+	var record []string
+	add := func(name string, data []byte, mode os.FileMode) error {
+		h := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: buildTime()}
+		h.SetMode(mode)
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		record = append(record, fmt.Sprintf("%s,sha256=%s,%d", recordPath(name), base64.RawURLEncoding.EncodeToString(sum[:]), len(data)))
+		return nil
+	}
+
+	files := append([]buildFile(nil), b.packages...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	for _, f := range files {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return "", err
+		}
+		if err := add(f.Name, data, fileMode(f.Path)); err != nil {
+			return "", err
+		}
+	}
+
+	wheel := "Wheel-Version: 1.0\nGenerator: pigo\nRoot-Is-Purelib: true\nTag: py3-none-any\n"
+	if err := add(distInfo+"/METADATA", b.meta.Format(), 0644); err != nil {
+		return "", err
+	}
+	if err := add(distInfo+"/WHEEL", []byte(wheel), 0644); err != nil {
+		return "", err
+	}
+	if b.entryPoints != nil {
+		if err := add(distInfo+"/entry_points.txt", b.entryPoints, 0644); err != nil {
+			return "", err
+		}
+	}
+	for _, f := range b.licenses {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return "", err
+		}
+		if err := add(distInfo+"/licenses/"+f.Name, data, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	// RECORD 는 자기 자신의 해시 없이 마지막에 씁니다.
+	record = append(record, recordPath(distInfo+"/RECORD")+",,")
+	h := &zip.FileHeader{Name: distInfo + "/RECORD", Method: zip.Deflate, Modified: buildTime()}
+	h.SetMode(0644)
+	w, err := zw.CreateHeader(h)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, strings.Join(record, "\n")+"\n"); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	return path, os.Rename(tmp.Name(), path)
+}
+
+// recordPath 는 RECORD (csv) 에 쓸 수 있게 경로를 따옴표로 감쌉니다.
+func recordPath(name string) string {
+	if strings.ContainsAny(name, ",\"") {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return name
+}
+
+// writeSdist 는 outDir 에 <name>-<version>.tar.gz 를 만듭니다. .gitignore 에서 무시하는 파일,
+// 숨김 디렉토리, .env, outDir 과 vendor/ 를 뺀 프로젝트 전체와 PKG-INFO 가 들어갑니다.
+func (b *nativeBuild) writeSdist(outDir string) (string, error) {
+	prefix := importName(b.meta.Name) + "-" + b.meta.Version
+	path := filepath.Join(outDir, prefix+".tar.gz")
+
+	files, err := sdistFiles(b.root, outDir)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(outDir, ".pigo-sdist-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	gz.ModTime = buildTime()
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte, mode os.FileMode) error {
+		h := &tar.Header{
+			Name:    prefix + "/" + name,
+			Mode:    int64(mode),
+			Size:    int64(len(data)),
+			ModTime: buildTime(),
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := add("PKG-INFO", b.meta.Format(), 0644); err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return "", err
+		}
+		if err := add(f.Name, data, fileMode(f.Path)); err != nil {
+			return "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	return path, os.Rename(tmp.Name(), path)
+}
+
+// sdistFiles 는 sdist 에 넣을 프로젝트 파일을 이름 순서로 찾습니다.
+func sdistFiles(root, outDir string) ([]buildFile, error) {
+	ignore := readIgnoreRules(root)
+	skipDirs := map[string]bool{}
+	for _, dir := range []string{outDir, vendorDir()} {
+		if abs, err := filepath.Abs(dir); err == nil {
+			skipDirs[abs] = true
+		}
+	}
+
+	var files []buildFile
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		name := d.Name()
+		if d.IsDir() {
+			if strings.HasPrefix(name, ".") || name == "__pycache__" || skipDirs[path] || ignore.match(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || name == dotenvFileName || name == "PKG-INFO" ||
+			filepath.Ext(name) == ".pyc" || ignore.match(rel, false) {
+			return nil
+		}
+		files = append(files, buildFile{Path: path, Name: filepath.ToSlash(rel)})
+		return nil
+	})
+	return files, err
+}
+
+var buildCmd = &cobra.Command{
+	Use:   "build [./...]",
+	Short: "Build a wheel and an sdist of the project",
+	Long: `Builds dist/<name>-<version>.tar.gz and dist/<name>-<version>-py3-none-any.whl
+from the [project] table of pyproject.toml (PEP 517, PEP 621).
+
+Pure-Python projects with static metadata are built by pigo itself, without a
+build backend or network access. The wheel contains the package named after
+the project (or every top-level package, src/ layout included) with METADATA,
+WHEEL, entry_points.txt and RECORD; the sdist contains every file not ignored
+by .gitignore plus PKG-INFO. requires-python defaults to the python pinned in
+pigo.mod. dependencies must be listed in [project]: the build fails if it is
+missing while requirements.txt has entries, since a wheel rebuilt from the
+sdist would lose them.
+
+Other projects (setup.py, extension modules, dynamic metadata, an unknown build
+backend or its [tool.*] build settings) are built by the backend declared in
+[build-system], installed into a temporary virtual environment.
+--use-backend always does that.
+
+With ./... every pigo.work member is built.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var opts buildOptions
+		opts.OutDir, _ = cmd.Flags().GetString("outdir")
+		opts.Wheel, _ = cmd.Flags().GetBool("wheel")
+		opts.Sdist, _ = cmd.Flags().GetBool("sdist")
+		opts.UseBackend, _ = cmd.Flags().GetBool("use-backend")
+		if !opts.Wheel && !opts.Sdist {
+			opts.Wheel, opts.Sdist = true, true
+		}
+		if opts.OutDir != "" {
+			abs, err := filepath.Abs(opts.OutDir)
+			if err != nil {
+				log.Fatalf("error: %v", err)
+			}
+			opts.OutDir = abs
+		}
+
+		var err error
+		if _, ok := cutWorkspacePattern(args); ok {
+			err = forEachMember(func(member string) error {
+				return runBuild(opts)
+			})
+		} else if len(args) > 0 {
+			log.Fatalf("error: unexpected argument %q", args[0])
+		} else {
+			err = runBuild(opts)
+		}
+		if err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(buildCmd)
+
+	buildCmd.Flags().StringP("outdir", "o", "", "Write the distributions to this directory (default: dist/)")
+	buildCmd.Flags().Bool("wheel", false, "Build only the wheel")
+	buildCmd.Flags().Bool("sdist", false, "Build only the sdist")
+	buildCmd.Flags().Bool("use-backend", false, "Always build with the backend declared in [build-system]")
+}
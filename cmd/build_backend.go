@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/janghanul090801/pigo/cmd/venv"
+)
+
+// defaultBuildSystem 은 [build-system] 이 없을 때 쓰는 PEP 517 기본값입니다.
+var defaultBuildSystem = modfile.BuildSystem{
+	Requires:     []string{"setuptools>=40.8.0"},
+	BuildBackend: "setuptools.build_meta:__legacy__",
+}
+
+// buildHookScript 는 build backend 의 PEP 517 hook 하나를 부르고 결과를 JSON 파일에 씁니다.
+// argv: hook, 출력 디렉토리, 결과 파일, backend, backend-path...
+const buildHookScript = `
+import importlib, json, os, sys
+
+hook, out_dir, result, backend = sys.argv[1:5]
+# 프로젝트 디렉토리는 backend-path 로 적은 경우에만 import 경로에 넣습니다.
+sys.path[:] = [p for p in sys.path if p not in ("", os.getcwd())]
+for path in reversed(sys.argv[5:]):
+    sys.path.insert(0, os.path.abspath(path))
+
+module, _, attrs = backend.partition(":")
+obj = importlib.import_module(module)
+for attr in filter(None, attrs.split(".")):
+    obj = getattr(obj, attr)
+
+if hook.startswith("get_requires_for_build_"):
+    fn = getattr(obj, hook, None)
+    value = list(fn({})) if fn else []
+else:
+    value = getattr(obj, hook)(out_dir, {})
+with open(result, "w") as f:
+    json.dump(value, f)
+`
+
+// buildSystem 은 pyproject.toml 의 [build-system] 에 PEP 517 기본값을 채웁니다.
+func buildSystem(pp *modfile.Pyproject) modfile.BuildSystem {
+	if pp.BuildSystem == nil {
+		return defaultBuildSystem
+	}
+	bs := *pp.BuildSystem
+	if bs.Requires == nil {
+		bs.Requires = defaultBuildSystem.Requires
+	}
+	if bs.BuildBackend == "" {
+		bs.BuildBackend = defaultBuildSystem.BuildBackend
+	}
+	return bs
+}
+
+// buildWithBackend 는 임시 가상환경에 build-system.requires 를 설치하고
+// backend 의 build_sdist, build_wheel 로 outDir 에 배포판을 만듭니다.
+func buildWithBackend(bs modfile.BuildSystem, outDir string, opts buildOptions) ([]string, error) {
+	mf, err := modfile.Read(projectPath(modfile.FileName))
+	if err != nil {
+		return nil, err
+	}
+	interp, err := findInterpreter(mf.Python)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "pigo-build-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	envDir := filepath.Join(tmp, "env")
+	if err := createVenv(envDir, interp, true, os.Stderr); err != nil {
+		return nil, err
+	}
+	if err := buildEnvInstall(envDir, bs.Requires); err != nil {
+		return nil, err
+	}
+
+	var kinds []string
+	if opts.Sdist {
+		kinds = append(kinds, "sdist")
+	}
+	if opts.Wheel {
+		kinds = append(kinds, "wheel")
+	}
+	var built []string
+	for _, kind := range kinds {
+		var requires []string
+		if err := callBuildHook(envDir, tmp, bs, "get_requires_for_build_"+kind, "", &requires); err != nil {
+			return nil, err
+		}
+		if err := buildEnvInstall(envDir, requires); err != nil {
+			return nil, err
+		}
+		var name string
+		if err := callBuildHook(envDir, tmp, bs, "build_"+kind, outDir, &name); err != nil {
+			return nil, err
+		}
+		built = append(built, filepath.Join(outDir, name))
+	}
+	return built, nil
+}
+
+// buildEnvInstall 은 빌드용 가상환경에 reqs 를 설치합니다. offline 이면 로컬 디렉토리만 봅니다.
+func buildEnvInstall(envDir string, reqs []string) error {
+	if len(reqs) == 0 {
+		return nil
+	}
+	args := []string{"-m", "pip", "install", "--disable-pip-version-check", "--quiet"}
+	if offlineMode() {
+		args = append(args, offlinePipArgs()...)
+	}
+	c := exec.Command(venv.Python(envDir), append(args, reqs...)...)
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	if offlineMode() {
+		c.Env = offlineEnv()
+	}
+	if err := runProcess(context.Background(), c); err != nil {
+		return fmt.Errorf("installing build requirements: %w", err)
+	}
+	return nil
+}
+
+// callBuildHook 은 프로젝트 루트에서 backend 의 hook 을 부르고 결과를 result 에 읽습니다.
+func callBuildHook(envDir, tmp string, bs modfile.BuildSystem, hook, outDir string, result any) error {
+	resultFile := filepath.Join(tmp, hook+".json")
+	args := append([]string{"-c", buildHookScript, hook, outDir, resultFile, bs.BuildBackend}, bs.BackendPath...)
+	c := exec.Command(venv.Python(envDir), args...)
+	c.Dir = projectRoot()
+	// backend 의 출력은 stderr 로 보내고 stdout 에는 만든 파일만 출력합니다.
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	if offlineMode() {
+		c.Env = offlineEnv()
+	}
+	if err := runProcess(context.Background(), c); err != nil {
+		return fmt.Errorf("%s %s: %w", bs.BuildBackend, hook, err)
+	}
+	data, err := os.ReadFile(resultFile)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+)
+
+func TestProjectMetadataOptionalDependencies(t *testing.T) {
+	root := testProject(t)
+	p := &modfile.Project{
+		Name:           "demo",
+		Version:        "1.0",
+		RequiresPython: ">=3.9",
+		Dependencies:   []string{"requests>=2"},
+		OptionalDependencies: map[string][]string{
+			"Socks": {`pysocks; python_version < "3.12" or sys_platform == "win32"`},
+			"test":  {"pytest>=8"},
+		},
+	}
+	meta, _, err := projectMetadata(p, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDist := []string{
+		"requests>=2",
+		`pysocks; (python_version < "3.12" or sys_platform == "win32") and extra == "socks"`,
+		`pytest>=8; extra == "test"`,
+	}
+	if !reflect.DeepEqual(meta.RequiresDist, wantDist) {
+		t.Errorf("RequiresDist = %q, want %q", meta.RequiresDist, wantDist)
+	}
+	if want := []string{"socks", "test"}; !reflect.DeepEqual(meta.ProvidesExtra, want) {
+		t.Errorf("ProvidesExtra = %q, want %q", meta.ProvidesExtra, want)
+	}
+
+	// 합친 marker 는 extra 가 맞을 때만 참이어야 합니다.
+	req, err := dist.ParseRequirement(meta.RequiresDist[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := dist.Environment{"python_version": "3.11", "sys_platform": "linux"}
+	for extra, want := range map[string]bool{"socks": true, "test": false, "": false} {
+		var extras []string
+		if extra != "" {
+			extras = []string{extra}
+		}
+		got, err := dist.EvaluateMarker(req.Marker, env, extras)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("EvaluateMarker(%q, %q) = %v, want %v", req.Marker, extras, got, want)
+		}
+	}
+}
+
+func TestProjectMetadataStaticDependencies(t *testing.T) {
+	root := testProject(t)
+	p := &modfile.Project{Name: "demo", Version: "1.0", RequiresPython: ">=3.9"}
+
+	writeTestFile(t, filepath.Join(root, modfile.RequirementsFileName), "# 비어 있음\n")
+	meta, _, err := projectMetadata(p, root)
+	if err != nil {
+		t.Fatalf("empty requirements.txt: %v", err)
+	}
+	if len(meta.RequiresDist) != 0 {
+		t.Errorf("RequiresDist = %q, want none", meta.RequiresDist)
+	}
+
+	writeTestFile(t, filepath.Join(root, modfile.RequirementsFileName), "requests==2.31.0\nsix\n")
+	_, _, err = projectMetadata(p, root)
+	if err == nil || !strings.Contains(err.Error(), `dependencies = ["requests==2.31.0", "six"]`) {
+		t.Errorf("err = %v, want a hint listing requirements.txt entries", err)
+	}
+
+	p.Dependencies = []string{}
+	if _, _, err := projectMetadata(p, root); err != nil {
+		t.Errorf("explicit empty dependencies: %v", err)
+	}
+}
+
+func TestWriteWheelRecord(t *testing.T) {
+	root := testProject(t)
+	files := map[string]string{
+		"demo/__init__.py":  "VERSION = '1.0'\n",
+		"demo/a,b.py":       "x = 1\n",
+		`demo/"quoted".txt`: "",
+		"LICENSE":           "MIT\n",
+	}
+	b := &nativeBuild{
+		root:        root,
+		meta:        &dist.Metadata{Name: "demo", Version: "1.0", LicenseFiles: []string{"LICENSE"}},
+		entryPoints: []byte("[console_scripts]\ndemo = demo:main\n"),
+	}
+	for name, body := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		writeTestFile(t, path, body)
+		if name == "LICENSE" {
+			b.licenses = append(b.licenses, buildFile{Path: path, Name: name})
+		} else {
+			b.packages = append(b.packages, buildFile{Path: path, Name: name})
+		}
+	}
+
+	path, err := b.writeWheel(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	contents := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[f.Name] = data
+	}
+	const record = "demo-1.0.dist-info/RECORD"
+	rows, err := csv.NewReader(strings.NewReader(string(contents[record]))).ReadAll()
+	if err != nil {
+		t.Fatalf("RECORD is not valid csv: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, row := range rows {
+		if len(row) != 3 {
+			t.Fatalf("RECORD row %q has %d fields", row, len(row))
+		}
+		name := row[0]
+		seen[name] = true
+		if name == record {
+			if row[1] != "" || row[2] != "" {
+				t.Errorf("RECORD lists its own hash: %q", row)
+			}
+			continue
+		}
+		data, ok := contents[name]
+		if !ok {
+			t.Errorf("RECORD lists %q, which is not in the wheel", name)
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if want := "sha256=" + base64.RawURLEncoding.EncodeToString(sum[:]); row[1] != want {
+			t.Errorf("%s: hash %q, want %q", name, row[1], want)
+		}
+		if want := strconv.Itoa(len(data)); row[2] != want {
+			t.Errorf("%s: size %q, want %q", name, row[2], want)
+		}
+	}
+	for name := range contents {
+		if !seen[name] {
+			t.Errorf("%q is missing from RECORD", name)
+		}
+	}
+	for _, name := range []string{"demo/a,b.py", `demo/"quoted".txt`, "demo-1.0.dist-info/licenses/LICENSE", "demo-1.0.dist-info/entry_points.txt"} {
+		if !seen[name] {
+			t.Errorf("RECORD has no row for %q", name)
+		}
+	}
+}
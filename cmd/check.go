@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/spf13/cobra"
+)
+
+// import 이름과 PyPI 배포판 이름이 다른 흔한 경우들
+// (설치되어 있지 않으면 메타데이터로 알 수 없으므로 추천용으로만 사용합니다.)
+var knownDistributions = map[string]string{
+	"yaml": "PyYAML", "PIL": "Pillow", "cv2": "opencv-python", "sklearn": "scikit-learn",
+	"skimage": "scikit-image", "bs4": "beautifulsoup4", "dateutil": "python-dateutil",
+	"dotenv": "python-dotenv", "jose": "python-jose", "jwt": "PyJWT", "Crypto": "pycryptodome",
+	"serial": "pyserial", "usb": "pyusb", "magic": "python-magic", "attr": "attrs",
+	"google": "protobuf", "gi": "PyGObject", "win32api": "pywin32", "win32con": "pywin32",
+	"MySQLdb": "mysqlclient", "docx": "python-docx", "pptx": "python-pptx",
+	"telegram": "python-telegram-bot", "discord": "discord.py", "zmq": "pyzmq",
+	"OpenSSL": "pyOpenSSL", "fitz": "PyMuPDF", "multipart": "python-multipart",
+	"socks": "PySocks", "git": "GitPython", "slugify": "python-slugify", "Levenshtein": "python-Levenshtein",
+}
+
+// suggestDistribution 은 설치되지 않은 import 에 대해 설치할 requirement 를 추천합니다.
+func suggestDistribution(usage ImportUsage) string {
+	if usage.Declared && usage.Requirement != "" {
+		return usage.Requirement
+	}
+	if name, ok := knownDistributions[usage.Import]; ok {
+		return name
+	}
+	return strings.ReplaceAll(usage.Import, "_", "-")
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check [path]",
+	Short: "Report imported packages that are not installed in the venv",
+	Long: `Parses every .py file under path (default '.') and compares third-party imports
+with the distributions installed in .venv. Missing packages are reported with the
+file:line of each import and the pigo install command that would fix them.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		searchPath := projectRoot()
+		if len(args) > 0 {
+			searchPath = args[0]
+		}
+
+		if _, err := dist.SitePackages(filepath.Join(searchPath, ".venv")); err != nil {
+			log.Fatalf("error: %v (run 'pigo init' first)", err)
+		}
+
+		usages, err := collectImportUsage(searchPath)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+
+		var suggestions []string
+		for _, u := range usages {
+			if u.Installed {
+				continue
+			}
+			suggestion := suggestDistribution(u)
+			fmt.Printf("%s: not installed\n", u.Import)
+			for _, site := range u.Sites {
+				fmt.Printf("\t%s:%d\n", site.File, site.Line)
+			}
+			fmt.Printf("\tpigo install %s\n", quoteArg(suggestion))
+			suggestions = append(suggestions, quoteArg(suggestion))
+		}
+
+		if len(suggestions) == 0 {
+			fmt.Println("All imports are installed.")
+			return
+		}
+		fmt.Printf("\n%d imported packages are not installed. Run:\n\tpigo install %s\n", len(suggestions), strings.Join(suggestions, " "))
+		os.Exit(1)
+	},
+}
+
+// quoteArg 는 shell 에서 특수문자로 해석될 수 있는 인자를 따옴표로 감쌉니다.
+func quoteArg(s string) string {
+	if strings.ContainsAny(s, " <>;[]*?|&$!") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
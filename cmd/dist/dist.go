@@ -0,0 +1,421 @@
+package dist
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Distribution 은 site-packages 에 설치된 패키지 하나의 메타데이터입니다.
+// .dist-info 와 .egg-info 를 모두 같은 형태로 읽습니다.
+type Distribution struct {
+	Name        string
+	Version     string
+	Path        string // .dist-info / .egg-info 경로
+	ImportNames []string
+	Requires    []Requirement
+	Extras      []string
+	EntryPoints []EntryPoint
+	Files       []string   // site-packages 기준 상대경로
+	Editable    string     // editable 설치라면 소스 디렉토리
+	DirectURL   *DirectURL // VCS 나 로컬 경로에서 설치한 경우
+}
+
+type EntryPoint struct {
+	Group string
+	Name  string
+	Value string
+}
+
+var normalizeRe = regexp.MustCompile(`[-_.]+`)
+
+// NormalizeName 은 PEP 503 규칙으로 패키지 이름을 정규화합니다.
+// (Django, django_rest-framework -> django, django-rest-framework)
+func NormalizeName(name string) string {
+	return strings.ToLower(normalizeRe.ReplaceAllString(strings.TrimSpace(name), "-"))
+}
+
+// SitePackages 는 venv 안의 site-packages 디렉토리를 찾습니다.
+// linux 는 lib/pythonX.Y/site-packages, windows 는 Lib/site-packages 입니다.
+func SitePackages(venv string) ([]string, error) {
+	var dirs []string
+	for _, pattern := range []string{
+		filepath.Join(venv, "lib", "python*", "site-packages"),
+		filepath.Join(venv, "lib64", "python*", "site-packages"),
+		filepath.Join(venv, "Lib", "site-packages"),
+	} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.IsDir() && !containsPath(dirs, m) {
+				dirs = append(dirs, m)
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no site-packages found in %s", venv)
+	}
+	return dirs, nil
+}
+
+func containsPath(paths []string, p string) bool {
+	for _, existing := range paths {
+		if same, err := sameFile(existing, p); err == nil && same {
+			return true
+		}
+	}
+	return false
+}
+
+func sameFile(a, b string) (bool, error) {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(ia, ib), nil
+}
+
+// LoadVenv 는 venv 의 모든 site-packages 에서 설치된 배포판을 읽습니다.
+func LoadVenv(venv string) ([]*Distribution, error) {
+	dirs, err := SitePackages(venv)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Distribution
+	for _, dir := range dirs {
+		dists, err := Load(dir)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dists...)
+	}
+	return result, nil
+}
+
+// Load 는 site-packages 디렉토리 하나에서 *.dist-info, *.egg-info 를 읽습니다.
+func Load(sitePackages string) ([]*Distribution, error) {
+	entries, err := os.ReadDir(sitePackages)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Distribution
+	for _, e := range entries {
+		path := filepath.Join(sitePackages, e.Name())
+		var d *Distribution
+		switch {
+		case strings.HasSuffix(e.Name(), ".dist-info") && e.IsDir():
+			d, err = readDistInfo(path)
+		case strings.HasSuffix(e.Name(), ".egg-info"):
+			d, err = readEggInfo(path, e.IsDir())
+		case strings.HasSuffix(e.Name(), ".egg-link"):
+			d, err = readEggLink(path)
+		default:
+			continue
+		}
+		if os.IsNotExist(err) {
+			// 지우다 남은 .dist-info (METADATA 없음) 는 pip 과 같이 무시합니다.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		if d == nil {
+			continue
+		}
+		if d.ImportNames == nil {
+			d.ImportNames = importNamesFromFiles(d.Files)
+		}
+		if d.ImportNames == nil && d.Editable != "" {
+			// editable 설치의 RECORD 에는 .pth 와 __editable__ 파일뿐이므로
+			// 소스 디렉토리에서 이름을 찾습니다.
+			d.ImportNames = LocalImportNames(d.Editable)
+		}
+		if len(d.ImportNames) == 0 {
+			// 최후의 수단: 이름 변환
+			d.ImportNames = []string{strings.ReplaceAll(strings.ToLower(d.Name), "-", "_")}
+		}
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return NormalizeName(result[i].Name) < NormalizeName(result[j].Name)
+	})
+	return result, nil
+}
+
+// Index 는 정규화된 이름으로 배포판을 찾을 수 있게 map 을 만듭니다.
+func Index(dists []*Distribution) map[string]*Distribution {
+	m := make(map[string]*Distribution, len(dists))
+	for _, d := range dists {
+		m[NormalizeName(d.Name)] = d
+	}
+	return m
+}
+
+func readDistInfo(path string) (*Distribution, error) {
+	meta, err := os.ReadFile(filepath.Join(path, "METADATA"))
+	if err != nil {
+		return nil, err
+	}
+	d := newDistribution(path, parseHeaders(string(meta)))
+	d.DirectURL = readDirectURL(path)
+	d.Editable = d.DirectURL.editablePath()
+
+	if data, err := os.ReadFile(filepath.Join(path, "RECORD")); err == nil {
+		d.Files = parseRecord(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "top_level.txt")); err == nil {
+		d.ImportNames = parseTopLevel(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "entry_points.txt")); err == nil {
+		d.EntryPoints = ParseEntryPoints(string(data))
+	}
+	return d, nil
+}
+
+func readEggInfo(path string, isDir bool) (*Distribution, error) {
+	if !isDir {
+		// 오래된 distutils 는 PKG-INFO 내용을 .egg-info 파일 하나로 남깁니다.
+		meta, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return newDistribution(path, parseHeaders(string(meta))), nil
+	}
+
+	meta, err := os.ReadFile(filepath.Join(path, "PKG-INFO"))
+	if err != nil {
+		return nil, err
+	}
+	d := newDistribution(path, parseHeaders(string(meta)))
+
+	if data, err := os.ReadFile(filepath.Join(path, "requires.txt")); err == nil {
+		d.Requires = append(d.Requires, parseRequiresTxt(string(data))...)
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "installed-files.txt")); err == nil {
+		// installed-files.txt 는 egg-info 기준 상대경로이므로 site-packages 기준으로 바꿉니다.
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			rel := filepath.ToSlash(filepath.Clean(filepath.Join(filepath.Base(path), line)))
+			d.Files = append(d.Files, rel)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "top_level.txt")); err == nil {
+		d.ImportNames = parseTopLevel(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "entry_points.txt")); err == nil {
+		d.EntryPoints = ParseEntryPoints(string(data))
+	}
+	return d, nil
+}
+
+func newDistribution(path string, headers map[string][]string) *Distribution {
+	d := &Distribution{
+		Name:    first(headers["name"]),
+		Version: first(headers["version"]),
+		Path:    path,
+		Extras:  headers["provides-extra"],
+	}
+	if d.Name == "" {
+		d.Name = nameFromInfoDir(filepath.Base(path))
+	}
+	for _, raw := range headers["requires-dist"] {
+		if req, err := ParseRequirement(raw); err == nil {
+			d.Requires = append(d.Requires, req)
+		}
+	}
+	return d
+}
+
+// nameFromInfoDir 는 requests-2.31.0.dist-info 같은 디렉토리명에서 이름을 추출합니다.
+func nameFromInfoDir(base string) string {
+	base = strings.TrimSuffix(strings.TrimSuffix(base, ".dist-info"), ".egg-info")
+	if idx := strings.Index(base, "-"); idx != -1 {
+		base = base[:idx]
+	}
+	return base
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// parseHeaders 는 METADATA / PKG-INFO 의 RFC 822 스타일 헤더를 읽습니다.
+// 빈 줄 이후의 본문(Description)은 무시합니다.
+func parseHeaders(content string) map[string][]string {
+	headers := make(map[string][]string)
+	var lastKey string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if line == "" {
+			break
+		}
+		if (line[0] == ' ' || line[0] == '\t') && lastKey != "" {
+			// 여러 줄에 걸친 헤더 값
+			values := headers[lastKey]
+			values[len(values)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		idx := strings.Index(line, ":")
+		if idx == -1 {
+			continue
+		}
+		lastKey = strings.ToLower(strings.TrimSpace(line[:idx]))
+		headers[lastKey] = append(headers[lastKey], strings.TrimSpace(line[idx+1:]))
+	}
+	return headers
+}
+
+// parseRequiresTxt 는 egg-info 의 requires.txt 를 읽습니다.
+// [extra], [extra:marker], [:marker] 섹션은 marker 로 변환합니다.
+func parseRequiresTxt(content string) []Requirement {
+	var reqs []Requirement
+	var sectionMarker string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section := line[1 : len(line)-1]
+			extra, marker, _ := strings.Cut(section, ":")
+			var parts []string
+			if extra = strings.TrimSpace(extra); extra != "" {
+				parts = append(parts, fmt.Sprintf(`extra == "%s"`, extra))
+			}
+			if marker = strings.TrimSpace(marker); marker != "" {
+				parts = append(parts, "("+marker+")")
+			}
+			sectionMarker = strings.Join(parts, " and ")
+			continue
+		}
+		req, err := ParseRequirement(line)
+		if err != nil {
+			continue
+		}
+		if sectionMarker != "" {
+			if req.Marker != "" {
+				req.Marker = "(" + req.Marker + ") and " + sectionMarker
+			} else {
+				req.Marker = sectionMarker
+			}
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// parseRecord 는 RECORD (csv: path,hash,size) 에서 경로만 뽑습니다.
+// 콤마나 따옴표가 들어간 경로는 csv 규칙대로 따옴표로 감싸져 있습니다.
+func parseRecord(content string) []string {
+	r := csv.NewReader(strings.NewReader(content))
+	r.FieldsPerRecord = -1
+	var files []string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			// 깨진 줄은 건너뛰고 나머지를 읽습니다.
+			continue
+		}
+		if path := strings.TrimSpace(record[0]); path != "" {
+			files = append(files, filepath.ToSlash(path))
+		}
+	}
+	return files
+}
+
+func parseTopLevel(content string) []string {
+	var names []string
+	for _, line := range strings.Split(content, "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" {
+			continue
+		}
+		// foo/bar 형태는 namespace 패키지이므로 최상위만 사용합니다.
+		name = strings.Split(strings.ReplaceAll(name, "\\", "/"), "/")[0]
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// importNamesFromFiles 는 top_level.txt 가 없을 때 설치된 파일 경로로 import 이름을 추출합니다.
+// 예: python-jose -> jose/__init__.py -> 'jose'
+func importNamesFromFiles(files []string) []string {
+	var names []string
+	for _, f := range files {
+		parts := strings.Split(f, "/")
+		top := parts[0]
+		if top == "" || top == ".." || top == "__pycache__" ||
+			strings.HasSuffix(top, ".dist-info") || strings.HasSuffix(top, ".egg-info") ||
+			strings.HasPrefix(top, "__editable__") {
+			continue
+		}
+		var name string
+		if len(parts) == 1 {
+			// six.py, _cffi_backend.cpython-312-x86_64-linux-gnu.so 같은 단일 모듈
+			ext := filepath.Ext(top)
+			switch ext {
+			case ".py", ".so", ".pyd":
+				name = strings.SplitN(strings.TrimSuffix(top, ext), ".", 2)[0]
+			default:
+				continue
+			}
+		} else {
+			name = top
+		}
+		if !isIdentifier(name) || slices.Contains(names, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func isIdentifier(s string) bool {
+	return identifierRe.MatchString(s)
+}
+
+// ParseEntryPoints 는 entry_points.txt (ini 형식) 를 읽습니다.
+func ParseEntryPoints(content string) []EntryPoint {
+	var eps []EntryPoint
+	var group string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			group = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || group == "" {
+			continue
+		}
+		eps = append(eps, EntryPoint{Group: group, Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	return eps
+}
@@ -0,0 +1,189 @@
+package dist
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeSite 는 files (site-packages 기준 경로 -> 내용) 로 테스트용 site-packages 를 만듭니다.
+func writeSite(t *testing.T, files map[string]string) string {
+	t.Helper()
+	site := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(site, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return site
+}
+
+func loadIndex(t *testing.T, site string) map[string]*Distribution {
+	t.Helper()
+	dists, err := Load(site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Index(dists)
+}
+
+func requirementStrings(reqs []Requirement) []string {
+	var result []string
+	for _, r := range reqs {
+		result = append(result, r.String())
+	}
+	return result
+}
+
+func TestLoadDistInfo(t *testing.T) {
+	site := writeSite(t, map[string]string{
+		"python_jose-3.3.0.dist-info/METADATA": "Metadata-Version: 2.1\n" +
+			"Name: python-jose\n" +
+			"Version: 3.3.0\n" +
+			"Summary: JOSE implementation\n" +
+			"  continued summary\n" +
+			"Requires-Dist: ecdsa !=0.15\n" +
+			"Requires-Dist: cryptography >=3.4.0 ; extra == 'cryptography'\n" +
+			"Provides-Extra: cryptography\n" +
+			"\n" +
+			"Requires-Dist: not-a-header\n",
+		"python_jose-3.3.0.dist-info/RECORD": "jose/__init__.py,sha256=abc,10\n" +
+			"jose/backends/base.py,,\n" +
+			"\"jose/data/a,b.json\",sha256=def,3\n" +
+			"\"jose/data/say \"\"hi\"\".txt\",,\n" +
+			"single.py,,\n" +
+			"_speedups.cpython-312-x86_64-linux-gnu.so,,\n" +
+			"__pycache__/single.cpython-312.pyc,,\n" +
+			"../../../bin/jose,,\n" +
+			"python_jose-3.3.0.dist-info/METADATA,,\n" +
+			"not-an-identifier.py,,\n" +
+			"data.json,,\n",
+		"python_jose-3.3.0.dist-info/entry_points.txt": "[console_scripts]\njose = jose.cli:main\n",
+		// METADATA 가 없는 (지우다 남은) dist-info 는 무시합니다.
+		"leftover-1.0.dist-info/RECORD": "leftover/__init__.py,,\n",
+		// top_level.txt 가 있으면 파일 목록보다 우선합니다.
+		"attrs-23.1.0.dist-info/METADATA":      "Name: attrs\nVersion: 23.1.0\n",
+		"attrs-23.1.0.dist-info/RECORD":        "attr/__init__.py,,\nattrs/__init__.py,,\n",
+		"attrs-23.1.0.dist-info/top_level.txt": "attr\nattrs\nattr\n",
+	})
+	dists := loadIndex(t, site)
+	if _, ok := dists["leftover"]; ok {
+		t.Error("dist-info without METADATA was loaded")
+	}
+
+	jose := dists["python-jose"]
+	if jose == nil {
+		t.Fatalf("python-jose not loaded: %v", dists)
+	}
+	if jose.Version != "3.3.0" {
+		t.Errorf("Version = %q", jose.Version)
+	}
+	if want := []string{"ecdsa!=0.15", `cryptography>=3.4.0; extra == 'cryptography'`}; !reflect.DeepEqual(requirementStrings(jose.Requires), want) {
+		t.Errorf("Requires = %q, want %q", requirementStrings(jose.Requires), want)
+	}
+	if want := []string{"cryptography"}; !reflect.DeepEqual(jose.Extras, want) {
+		t.Errorf("Extras = %q, want %q", jose.Extras, want)
+	}
+	wantFiles := []string{
+		"jose/__init__.py", "jose/backends/base.py", "jose/data/a,b.json", `jose/data/say "hi".txt`,
+		"single.py", "_speedups.cpython-312-x86_64-linux-gnu.so", "__pycache__/single.cpython-312.pyc",
+		"../../../bin/jose", "python_jose-3.3.0.dist-info/METADATA", "not-an-identifier.py", "data.json",
+	}
+	if !reflect.DeepEqual(jose.Files, wantFiles) {
+		t.Errorf("Files = %q, want %q", jose.Files, wantFiles)
+	}
+	if want := []string{"_speedups", "jose", "single"}; !reflect.DeepEqual(jose.ImportNames, want) {
+		t.Errorf("ImportNames = %q, want %q", jose.ImportNames, want)
+	}
+	if want := []EntryPoint{{Group: "console_scripts", Name: "jose", Value: "jose.cli:main"}}; !reflect.DeepEqual(jose.EntryPoints, want) {
+		t.Errorf("EntryPoints = %+v, want %+v", jose.EntryPoints, want)
+	}
+
+	if attrs := dists["attrs"]; attrs == nil || !reflect.DeepEqual(attrs.ImportNames, []string{"attr", "attrs"}) {
+		t.Errorf("attrs = %+v, want ImportNames [attr attrs]", attrs)
+	}
+}
+
+func TestLoadEggInfo(t *testing.T) {
+	site := writeSite(t, map[string]string{
+		"requests-2.0.0-py3.8.egg-info/PKG-INFO": "Metadata-Version: 1.1\nName: requests\nVersion: 2.0.0\n",
+		"requests-2.0.0-py3.8.egg-info/requires.txt": "urllib3<3\n" +
+			"idna ; python_version >= \"3\"\n" +
+			"\n" +
+			"[socks]\n" +
+			"PySocks!=1.5.7\n" +
+			"\n" +
+			"[:python_version < \"3.8\"]\n" +
+			"importlib-metadata\n" +
+			"\n" +
+			"[security:sys_platform == \"win32\"]\n" +
+			"# comment\n" +
+			"pywin32\n",
+		"requests-2.0.0-py3.8.egg-info/installed-files.txt": "../requests/__init__.py\n../requests/api.py\nPKG-INFO\n",
+		"requests-2.0.0-py3.8.egg-info/top_level.txt":       "requests\nns/sub\n",
+		// 오래된 distutils 가 남긴 파일 하나짜리 egg-info
+		"legacy_pkg-0.1-py3.8.egg-info": "Metadata-Version: 1.0\nName: legacy-pkg\nVersion: 0.1\n",
+		// Name 이 없으면 디렉토리 이름에서 읽습니다.
+		"noname-0.2.egg-info/PKG-INFO": "Metadata-Version: 1.0\nVersion: 0.2\n",
+	})
+	dists := loadIndex(t, site)
+
+	requests := dists["requests"]
+	if requests == nil {
+		t.Fatalf("requests not loaded: %v", dists)
+	}
+	wantReqs := []string{
+		"urllib3<3",
+		`idna; python_version >= "3"`,
+		`PySocks!=1.5.7; extra == "socks"`,
+		`importlib-metadata; (python_version < "3.8")`,
+		`pywin32; extra == "security" and (sys_platform == "win32")`,
+	}
+	if got := requirementStrings(requests.Requires); !reflect.DeepEqual(got, wantReqs) {
+		t.Errorf("Requires = %q, want %q", got, wantReqs)
+	}
+	if want := []string{"requests/__init__.py", "requests/api.py", "requests-2.0.0-py3.8.egg-info/PKG-INFO"}; !reflect.DeepEqual(requests.Files, want) {
+		t.Errorf("Files = %q, want %q", requests.Files, want)
+	}
+	if want := []string{"requests", "ns"}; !reflect.DeepEqual(requests.ImportNames, want) {
+		t.Errorf("ImportNames = %q, want %q", requests.ImportNames, want)
+	}
+
+	if legacy := dists["legacy-pkg"]; legacy == nil || legacy.Version != "0.1" || !reflect.DeepEqual(legacy.ImportNames, []string{"legacy_pkg"}) {
+		t.Errorf("legacy-pkg = %+v", legacy)
+	}
+	if noname := dists["noname"]; noname == nil || noname.Version != "0.2" {
+		t.Errorf("noname = %+v", noname)
+	}
+}
+
+func TestParseRequiresTxtMarkers(t *testing.T) {
+	env := Environment{"python_version": "3.12", "sys_platform": "linux"}
+	reqs := parseRequiresTxt("a\n[x]\nb\n[:python_version < \"3.8\"]\nc\n[y:sys_platform == \"linux\"]\nd; python_version >= \"3.10\"\n")
+	want := map[string]map[string]bool{
+		"a": {"": true, "x": true, "y": true},
+		"b": {"": false, "x": true, "y": false},
+		"c": {"": false, "x": false, "y": false},
+		"d": {"": false, "x": false, "y": true},
+	}
+	if len(reqs) != len(want) {
+		t.Fatalf("parseRequiresTxt = %q", requirementStrings(reqs))
+	}
+	for _, req := range reqs {
+		for extra, expected := range want[req.Name] {
+			var extras []string
+			if extra != "" {
+				extras = []string{extra}
+			}
+			got, err := EvaluateMarker(req.Marker, env, extras)
+			if err != nil || got != expected {
+				t.Errorf("%s with extras %q = %v, %v; want %v", req.String(), extras, got, err, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package dist
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// DirectURL 은 PEP 610 direct_url.json 입니다. 인덱스가 아닌 곳
+// (VCS, 로컬 디렉토리)에서 설치한 패키지에만 있습니다.
+type DirectURL struct {
+	URL          string `json:"url"`
+	Subdirectory string `json:"subdirectory,omitempty"`
+	VCSInfo      *struct {
+		VCS               string `json:"vcs"`
+		CommitID          string `json:"commit_id"`
+		RequestedRevision string `json:"requested_revision,omitempty"`
+	} `json:"vcs_info,omitempty"`
+	DirInfo *struct {
+		Editable bool `json:"editable"`
+	} `json:"dir_info,omitempty"`
+}
+
+// readDirectURL 은 dist-info 의 direct_url.json 을 읽습니다. 없으면 nil 입니다.
+func readDirectURL(distInfo string) *DirectURL {
+	data, err := os.ReadFile(filepath.Join(distInfo, "direct_url.json"))
+	if err != nil {
+		return nil
+	}
+	var du DirectURL
+	if err := json.Unmarshal(data, &du); err != nil {
+		return nil
+	}
+	return &du
+}
+
+// editablePath 는 editable 설치(pip install -e)라면 소스 디렉토리를 돌려줍니다.
+func (du *DirectURL) editablePath() string {
+	if du == nil || du.DirInfo == nil || !du.DirInfo.Editable {
+		return ""
+	}
+	return fileURLPath(du.URL)
+}
+
+// LocalPath 는 URL 이 file:// 이면 로컬 경로를 돌려줍니다.
+func (du *DirectURL) LocalPath() string {
+	if du == nil {
+		return ""
+	}
+	return fileURLPath(du.URL)
+}
+
+// fileURLPath 는 file:// URL 을 로컬 경로로 바꿉니다.
+func fileURLPath(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	path := u.Path
+	if runtime.GOOS == "windows" {
+		// file:///C:/src -> C:/src
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path)
+}
+
+// readEggLink 는 setup.py develop 방식의 <name>.egg-link 를 읽습니다.
+// 메타데이터는 소스 디렉토리의 *.egg-info 에 있습니다.
+func readEggLink(path string) (*Distribution, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	src := strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0])
+	matches, _ := filepath.Glob(filepath.Join(src, "*.egg-info"))
+	if len(matches) == 0 {
+		matches, _ = filepath.Glob(filepath.Join(src, "src", "*.egg-info"))
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	d, err := readEggInfo(matches[0], true)
+	if err != nil {
+		return nil, err
+	}
+	d.Editable = src
+	return d, nil
+}
+
+// LocalImportNames 는 소스 디렉토리에서 최상위 패키지와 모듈 이름을 찾습니다.
+// src/ 레이아웃도 지원하며, setup.py 나 테스트 파일 같은 것은 제외합니다.
+func LocalImportNames(dir string) []string {
+	root := dir
+	if info, err := os.Stat(filepath.Join(dir, "src")); err == nil && info.IsDir() {
+		root = filepath.Join(dir, "src")
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			if _, err := os.Stat(filepath.Join(root, name, "__init__.py")); err != nil {
+				continue
+			}
+		} else {
+			if filepath.Ext(name) != ".py" {
+				continue
+			}
+			name = strings.TrimSuffix(name, ".py")
+			if name == "setup" || name == "conftest" || name == "noxfile" ||
+				strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test") {
+				continue
+			}
+		}
+		if name == "tests" || name == "test" || !isIdentifier(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,63 @@
+package dist
+
+import (
+	"slices"
+	"sort"
+)
+
+// Closure 는 roots 에서 시작해 marker 가 참인 의존성만 따라가며 도달 가능한 배포판을 모읍니다.
+// 결과는 정규화된 이름 -> 활성화된 extras 입니다. 설치되지 않은 패키지도 결과에는 포함되지만
+// 그 아래로는 따라가지 않습니다.
+func Closure(installed map[string]*Distribution, roots []Requirement, env Environment) map[string][]string {
+	result := make(map[string][]string)
+
+	type node struct {
+		name   string
+		extras []string
+	}
+	var queue []node
+
+	// visit 은 처음 보는 패키지이거나 새 extra 가 켜졌을 때만 다시 탐색하도록 큐에 넣습니다.
+	visit := func(req Requirement) {
+		name := NormalizeName(req.Name)
+		existing, seen := result[name]
+		var added []string
+		for _, extra := range req.Extras {
+			extra = NormalizeName(extra)
+			if !slices.Contains(existing, extra) && !slices.Contains(added, extra) {
+				added = append(added, extra)
+			}
+		}
+		if seen && len(added) == 0 {
+			return
+		}
+		merged := append(slices.Clone(existing), added...)
+		sort.Strings(merged)
+		result[name] = merged
+		queue = append(queue, node{name: name, extras: merged})
+	}
+
+	for _, root := range roots {
+		if ok, err := EvaluateMarker(root.Marker, env, nil); err == nil && ok {
+			visit(root)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		d, ok := installed[n.name]
+		if !ok {
+			continue
+		}
+		for _, dep := range d.Requires {
+			ok, err := EvaluateMarker(dep.Marker, env, n.extras)
+			if err != nil || !ok {
+				continue
+			}
+			visit(dep)
+		}
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package dist
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testInstalled 는 이름 -> Requires-Dist 줄로 설치된 배포판 목록을 만듭니다.
+func testInstalled(t *testing.T, requires map[string][]string) map[string]*Distribution {
+	t.Helper()
+	installed := make(map[string]*Distribution)
+	for name, lines := range requires {
+		d := &Distribution{Name: name, Version: "1.0"}
+		for _, line := range lines {
+			req, err := ParseRequirement(line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d.Requires = append(d.Requires, req)
+		}
+		installed[NormalizeName(name)] = d
+	}
+	return installed
+}
+
+func testRoots(t *testing.T, lines ...string) []Requirement {
+	t.Helper()
+	var roots []Requirement
+	for _, line := range lines {
+		req, err := ParseRequirement(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, req)
+	}
+	return roots
+}
+
+func TestClosure(t *testing.T) {
+	env := Environment{
+		"python_version":      "3.12",
+		"python_full_version": "3.12.1",
+		"sys_platform":        "linux",
+		"platform_system":     "Linux",
+		"os_name":             "posix",
+		"implementation_name": "cpython",
+	}
+	installed := testInstalled(t, map[string][]string{
+		"app": {`requests[socks]>=2`, `tomli; python_version < "3.11"`, `missing-pkg`},
+		"requests": {
+			`urllib3<3`,
+			`charset_normalizer`,
+			`PySocks!=1.5.7; extra == "socks"`,
+			`chardet; extra == "use-chardet"`,
+			`win-inet-pton; sys_platform == "win32" and extra == "socks"`,
+		},
+		"urllib3":            {`brotli; extra == "brotli"`},
+		"charset-normalizer": nil,
+		"pysocks":            nil,
+		"chardet":            nil,
+		"tomli":              nil,
+		"brotli":             nil,
+		"cycle-a":            {`cycle-b`},
+		"cycle-b":            {`cycle-c[x]`},
+		"cycle-c":            {`cycle-a`, `brotli; extra == "x"`},
+	})
+
+	tests := []struct {
+		name  string
+		roots []Requirement
+		want  map[string][]string
+	}{
+		{
+			name:  "no extras",
+			roots: testRoots(t, "requests"),
+			want:  map[string][]string{"requests": nil, "urllib3": nil, "charset-normalizer": nil},
+		},
+		{
+			name:  "extra requested",
+			roots: testRoots(t, "Requests[SOCKS]"),
+			want:  map[string][]string{"requests": {"socks"}, "urllib3": nil, "charset-normalizer": nil, "pysocks": nil},
+		},
+		{
+			name:  "transitive with false markers",
+			roots: testRoots(t, "app"),
+			want: map[string][]string{
+				"app": nil, "requests": {"socks"}, "urllib3": nil, "charset-normalizer": nil, "pysocks": nil,
+				"missing-pkg": nil,
+			},
+		},
+		{
+			name:  "extra added after first visit",
+			roots: testRoots(t, "requests", "urllib3", "app", "urllib3[brotli]"),
+			want: map[string][]string{
+				"app": nil, "requests": {"socks"}, "urllib3": {"brotli"}, "charset-normalizer": nil, "pysocks": nil,
+				"missing-pkg": nil, "brotli": nil,
+			},
+		},
+		{
+			name:  "root with false marker",
+			roots: testRoots(t, `tomli; python_version < "3.11"`, `requests; sys_platform == "linux"`),
+			want:  map[string][]string{"requests": nil, "urllib3": nil, "charset-normalizer": nil},
+		},
+		{
+			name:  "cycle",
+			roots: testRoots(t, "cycle-a"),
+			want:  map[string][]string{"cycle-a": nil, "cycle-b": nil, "cycle-c": {"x"}, "brotli": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Closure(installed, tt.roots, env)
+			for name, extras := range got {
+				if len(extras) == 0 {
+					got[name] = nil
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Closure = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
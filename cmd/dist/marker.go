@@ -0,0 +1,331 @@
+package dist
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Environment 는 PEP 508 marker 변수들입니다. (python_version, sys_platform ...)
+type Environment map[string]string
+
+// NewEnvironment 는 주어진 python 버전과 현재 OS 로 marker 환경을 만듭니다.
+func NewEnvironment(pythonVersion string) Environment {
+	parts := strings.Split(pythonVersion, ".")
+	short := pythonVersion
+	if len(parts) >= 2 {
+		short = parts[0] + "." + parts[1]
+	}
+
+	env := Environment{
+		"python_version":                 short,
+		"python_full_version":            pythonVersion,
+		"implementation_name":            "cpython",
+		"implementation_version":         pythonVersion,
+		"platform_python_implementation": "CPython",
+		"platform_release":               "",
+		"platform_version":               "",
+		"extra":                          "",
+	}
+	switch runtime.GOOS {
+	case "windows":
+		env["sys_platform"], env["platform_system"], env["os_name"] = "win32", "Windows", "nt"
+	case "darwin":
+		env["sys_platform"], env["platform_system"], env["os_name"] = "darwin", "Darwin", "posix"
+	default:
+		env["sys_platform"], env["platform_system"], env["os_name"] = runtime.GOOS, strings.ToUpper(runtime.GOOS[:1])+runtime.GOOS[1:], "posix"
+	}
+	switch runtime.GOARCH {
+	case "amd64":
+		env["platform_machine"] = "x86_64"
+		if runtime.GOOS == "windows" {
+			env["platform_machine"] = "AMD64"
+		}
+	case "arm64":
+		env["platform_machine"] = "aarch64"
+		if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+			env["platform_machine"] = "arm64"
+		}
+	case "386":
+		env["platform_machine"] = "i686"
+	default:
+		env["platform_machine"] = runtime.GOARCH
+	}
+	return env
+}
+
+// VenvEnvironment 는 venv 의 pyvenv.cfg 에서 python 버전을 읽어 marker 환경을 만듭니다.
+func VenvEnvironment(venv string) (Environment, error) {
+	version, err := VenvPythonVersion(venv)
+	if err != nil {
+		return nil, err
+	}
+	return NewEnvironment(version), nil
+}
+
+// VenvPythonVersion 은 venv 를 만든 python 의 버전 (3.12.1) 을 pyvenv.cfg 에서 읽습니다.
+func VenvPythonVersion(venv string) (string, error) {
+	cfg, err := ReadVenvConfig(venv)
+	if err != nil {
+		return "", err
+	}
+	version := cfg["version"]
+	if version == "" {
+		// virtualenv 는 version_info = 3.12.1.final.0 형식입니다.
+		parts := strings.Split(cfg["version_info"], ".")
+		if len(parts) >= 3 {
+			version = strings.Join(parts[:3], ".")
+		}
+	}
+	if version == "" {
+		return "", fmt.Errorf("%s: python version not found in pyvenv.cfg", venv)
+	}
+	return version, nil
+}
+
+// ReadVenvConfig 는 pyvenv.cfg 의 key = value 를 읽습니다.
+func ReadVenvConfig(venv string) (map[string]string, error) {
+	f, err := os.Open(filepath.Join(venv, "pyvenv.cfg"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cfg := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		cfg[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return cfg, scanner.Err()
+}
+
+// EvaluateMarker 는 marker 를 env 와 활성화된 extras 로 평가합니다.
+// extras 가 여러 개면 그 중 하나라도 참이면 참입니다. 빈 marker 는 항상 참입니다.
+func EvaluateMarker(marker string, env Environment, extras []string) (bool, error) {
+	if strings.TrimSpace(marker) == "" {
+		return true, nil
+	}
+	p := &markerParser{tokens: tokenizeMarker(marker)}
+	node, err := p.parseOr()
+	if err != nil {
+		return false, fmt.Errorf("invalid marker %q: %w", marker, err)
+	}
+	if p.pos != len(p.tokens) {
+		return false, fmt.Errorf("invalid marker %q: unexpected %q", marker, p.tokens[p.pos])
+	}
+
+	if len(extras) == 0 {
+		extras = []string{""}
+	}
+	for _, extra := range extras {
+		scoped := make(Environment, len(env)+1)
+		for k, v := range env {
+			scoped[k] = v
+		}
+		scoped["extra"] = NormalizeName(extra)
+		if node.eval(scoped) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// --- [marker 파서] ---
+
+type markerNode interface {
+	eval(env Environment) bool
+}
+
+type markerAnd struct{ left, right markerNode }
+type markerOr struct{ left, right markerNode }
+type markerCompare struct {
+	left, op, right   string
+	leftVar, rightVar bool
+}
+
+func (n markerAnd) eval(env Environment) bool { return n.left.eval(env) && n.right.eval(env) }
+func (n markerOr) eval(env Environment) bool  { return n.left.eval(env) || n.right.eval(env) }
+
+func (n markerCompare) eval(env Environment) bool {
+	left, right := n.left, n.right
+	if n.leftVar {
+		left = env[n.left]
+	}
+	if n.rightVar {
+		right = env[n.right]
+	}
+	// extra 는 이름 정규화 후 비교합니다.
+	if (n.leftVar && n.left == "extra") || (n.rightVar && n.right == "extra") {
+		left, right = NormalizeName(left), NormalizeName(right)
+	}
+
+	switch n.op {
+	case "in":
+		return strings.Contains(right, left)
+	case "not in":
+		return !strings.Contains(right, left)
+	}
+
+	_, okL := ParseVersion(left)
+	_, okR := ParseVersion(right)
+	if okL && okR {
+		return MatchSpecifier(left, n.op+right)
+	}
+	switch n.op {
+	case "==", "===":
+		return left == right
+	case "!=":
+		return left != right
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	}
+	return false
+}
+
+type markerParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *markerParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *markerParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *markerParser) parseOr() (markerNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = markerOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *markerParser) parseAnd() (markerNode, error) {
+	left, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" {
+		p.next()
+		right, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		left = markerAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *markerParser) parseAtom() (markerNode, error) {
+	if p.peek() == "(" {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		return node, nil
+	}
+
+	left, leftVar, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	op := p.next()
+	if op == "not" {
+		if p.next() != "in" {
+			return nil, fmt.Errorf("expected 'in' after 'not'")
+		}
+		op = "not in"
+	}
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=", "~=", "===", "in", "not in":
+	default:
+		return nil, fmt.Errorf("unexpected operator %q", op)
+	}
+	right, rightVar, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return markerCompare{left: left, op: op, right: right, leftVar: leftVar, rightVar: rightVar}, nil
+}
+
+func (p *markerParser) parseValue() (string, bool, error) {
+	t := p.next()
+	if t == "" {
+		return "", false, fmt.Errorf("unexpected end of marker")
+	}
+	if t[0] == '"' || t[0] == '\'' {
+		return t[1 : len(t)-1], false, nil
+	}
+	// os.name, sys.platform 같은 옛 이름도 허용합니다.
+	return strings.ReplaceAll(t, ".", "_"), true, nil
+}
+
+func tokenizeMarker(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end == -1 {
+				tokens = append(tokens, s[i:]+string(c))
+				return tokens
+			}
+			tokens = append(tokens, s[i:i+end+2])
+			i += end + 2
+		case strings.IndexByte("=!<>~", c) != -1:
+			j := i
+			for j < len(s) && strings.IndexByte("=!<>~", s[j]) != -1 {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(s) && strings.IndexByte(" \t()\"'=!<>~", s[j]) == -1 {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,65 @@
+package dist
+
+import "testing"
+
+func TestEvaluateMarker(t *testing.T) {
+	env := Environment{
+		"python_version":      "3.12",
+		"python_full_version": "3.12.1",
+		"sys_platform":        "linux",
+		"platform_system":     "Linux",
+		"os_name":             "posix",
+		"platform_machine":    "x86_64",
+		"implementation_name": "cpython",
+		"extra":               "",
+	}
+	tests := []struct {
+		marker string
+		extras []string
+		want   bool
+	}{
+		{``, nil, true},
+		{`python_version >= "3.8"`, nil, true},
+		{`python_version < "3.11"`, nil, false},
+		{`python_version < '3.11'`, nil, false},
+		{`python_version > "3.9"`, nil, true},
+		// 버전 비교이므로 문자열 비교와 달리 3.12 > 3.9 입니다.
+		{`python_full_version >= "3.12.0"`, nil, true},
+		{`"3.8" <= python_version`, nil, true},
+		{`sys_platform == "win32"`, nil, false},
+		{`sys_platform != "win32"`, nil, true},
+		{`os_name == "posix" and platform_machine == "x86_64"`, nil, true},
+		{`sys_platform == "win32" or sys_platform == "linux"`, nil, true},
+		{`(sys_platform == "win32" or python_version < "3.9") and os_name == "posix"`, nil, false},
+		{`sys_platform == "linux" and (python_version < "3.9" or os_name == "posix")`, nil, true},
+		{`extra == "dev"`, nil, false},
+		{`extra == "dev"`, []string{"dev"}, true},
+		{`extra == "dev-tools"`, []string{"Dev_Tools"}, true},
+		{`python_version >= "3.8" and extra == "socks"`, []string{"socks"}, true},
+		{`"linux" in sys_platform`, nil, true},
+		{`"win" not in sys_platform`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateMarker(tt.marker, env, tt.extras)
+		if err != nil {
+			t.Errorf("EvaluateMarker(%q) error: %v", tt.marker, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("EvaluateMarker(%q, extras=%v) = %v, want %v", tt.marker, tt.extras, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`python_version >=`, `(python_version > "3"`, `python_version ?? "3"`} {
+		if _, err := EvaluateMarker(bad, env, nil); err == nil {
+			t.Errorf("EvaluateMarker(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestNewEnvironment(t *testing.T) {
+	env := NewEnvironment("3.11.4")
+	if env["python_version"] != "3.11" || env["python_full_version"] != "3.11.4" {
+		t.Errorf("NewEnvironment(3.11.4) = %v", env)
+	}
+}
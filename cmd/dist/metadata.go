@@ -0,0 +1,80 @@
+package dist
+
+import (
+	"strings"
+)
+
+// Metadata 는 wheel 의 METADATA 와 sdist 의 PKG-INFO 에 쓰는 core metadata 입니다.
+type Metadata struct {
+	Name                   string
+	Version                string
+	Summary                string
+	Description            string // 헤더 뒤 본문으로 씁니다.
+	DescriptionContentType string
+	RequiresPython         string
+	License                string // license = {text/file} 의 내용
+	LicenseExpression      string // PEP 639 SPDX 표현식
+	LicenseFiles           []string
+	Author                 string
+	AuthorEmail            string
+	Maintainer             string
+	MaintainerEmail        string
+	Keywords               []string
+	Classifiers            []string
+	ProjectURLs            []string // "Homepage, https://..."
+	RequiresDist           []string
+	ProvidesExtra          []string
+}
+
+// Format 은 RFC 822 형식의 METADATA 내용을 돌려줍니다.
+// PEP 639 필드를 쓸 때만 Metadata-Version 2.4 를, 그 외에는 2.1 을 씁니다.
+func (m *Metadata) Format() []byte {
+	var b strings.Builder
+	header := func(name, value string) {
+		if value == "" {
+			return
+		}
+		// 여러 줄 값은 다음 줄을 공백으로 들여써서 이어 씁니다.
+		b.WriteString(name + ": " + strings.ReplaceAll(strings.TrimRight(value, "\n"), "\n", "\n        ") + "\n")
+	}
+
+	version := "2.1"
+	if m.LicenseExpression != "" || len(m.LicenseFiles) > 0 {
+		version = "2.4"
+	}
+	header("Metadata-Version", version)
+	header("Name", m.Name)
+	header("Version", m.Version)
+	header("Summary", m.Summary)
+	for _, u := range m.ProjectURLs {
+		header("Project-URL", u)
+	}
+	header("Author", m.Author)
+	header("Author-email", m.AuthorEmail)
+	header("Maintainer", m.Maintainer)
+	header("Maintainer-email", m.MaintainerEmail)
+	header("License", m.License)
+	header("License-Expression", m.LicenseExpression)
+	for _, f := range m.LicenseFiles {
+		header("License-File", f)
+	}
+	header("Keywords", strings.Join(m.Keywords, ","))
+	for _, c := range m.Classifiers {
+		header("Classifier", c)
+	}
+	header("Requires-Python", m.RequiresPython)
+	for _, r := range m.RequiresDist {
+		header("Requires-Dist", r)
+	}
+	for _, e := range m.ProvidesExtra {
+		header("Provides-Extra", e)
+	}
+	header("Description-Content-Type", m.DescriptionContentType)
+	if m.Description != "" {
+		b.WriteString("\n" + m.Description)
+		if !strings.HasSuffix(m.Description, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,71 @@
+package dist
+
+import "testing"
+
+func TestMetadataFormat(t *testing.T) {
+	m := &Metadata{
+		Name:                   "demo-pkg",
+		Version:                "1.0",
+		Summary:                "A demo",
+		Description:            "# Demo\n\nLong text.",
+		DescriptionContentType: "text/markdown",
+		RequiresPython:         ">=3.9",
+		License:                "MIT License\n\nCopyright (c) demo\n",
+		Author:                 "Ann",
+		AuthorEmail:            "Bob <bob@example.com>",
+		Keywords:               []string{"a", "b"},
+		Classifiers:            []string{"Programming Language :: Python :: 3"},
+		ProjectURLs:            []string{"Homepage, https://example.com"},
+		RequiresDist:           []string{"requests>=2", `pysocks; extra == "socks"`},
+		ProvidesExtra:          []string{"socks"},
+	}
+	want := "Metadata-Version: 2.1\n" +
+		"Name: demo-pkg\n" +
+		"Version: 1.0\n" +
+		"Summary: A demo\n" +
+		"Project-URL: Homepage, https://example.com\n" +
+		"Author: Ann\n" +
+		"Author-email: Bob <bob@example.com>\n" +
+		"License: MIT License\n" +
+		"        \n" +
+		"        Copyright (c) demo\n" +
+		"Keywords: a,b\n" +
+		"Classifier: Programming Language :: Python :: 3\n" +
+		"Requires-Python: >=3.9\n" +
+		"Requires-Dist: requests>=2\n" +
+		"Requires-Dist: pysocks; extra == \"socks\"\n" +
+		"Provides-Extra: socks\n" +
+		"Description-Content-Type: text/markdown\n" +
+		"\n" +
+		"# Demo\n\nLong text.\n"
+	if got := string(m.Format()); got != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+
+	// 헤더만 읽어도 여러 줄 값이 한 값으로 이어져야 합니다.
+	headers := parseHeaders(string(m.Format()))
+	if got := headers["license"]; len(got) != 1 || got[0] != "MIT License  Copyright (c) demo" {
+		t.Errorf("License header = %q", got)
+	}
+	if got := headers["requires-dist"]; len(got) != 2 {
+		t.Errorf("Requires-Dist headers = %q", got)
+	}
+}
+
+func TestMetadataFormatVersion(t *testing.T) {
+	tests := []struct {
+		meta Metadata
+		want string
+	}{
+		{Metadata{Name: "a", Version: "1"}, "2.1"},
+		{Metadata{Name: "a", Version: "1", License: "MIT"}, "2.1"},
+		{Metadata{Name: "a", Version: "1", LicenseExpression: "MIT"}, "2.4"},
+		{Metadata{Name: "a", Version: "1", LicenseFiles: []string{"LICENSE"}}, "2.4"},
+	}
+	for _, tt := range tests {
+		headers := parseHeaders(string(tt.meta.Format()))
+		if got := first(headers["metadata-version"]); got != tt.want {
+			t.Errorf("Metadata-Version for %+v = %q, want %q", tt.meta, got, tt.want)
+		}
+	}
+}
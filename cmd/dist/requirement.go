@@ -0,0 +1,87 @@
+package dist
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Requirement 는 PEP 508 의존성 한 줄입니다.
+// 예: pydantic[email] (>=2.0) ; python_version >= "3.8"
+type Requirement struct {
+	Name      string
+	Extras    []string
+	Specifier string // ">=2.0,<3" (괄호 제거)
+	URL       string // name @ url 형식일 때
+	Marker    string
+}
+
+var requirementNameRe = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?`)
+
+// ParseRequirement 는 Requires-Dist 값이나 requirements.txt 의 한 줄을 읽습니다.
+func ParseRequirement(s string) (Requirement, error) {
+	var req Requirement
+	s = strings.TrimSpace(s)
+
+	name := requirementNameRe.FindString(s)
+	if name == "" {
+		return req, fmt.Errorf("invalid requirement: %q", s)
+	}
+	req.Name = name
+	rest := strings.TrimSpace(s[len(name):])
+
+	if strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		if end == -1 {
+			return req, fmt.Errorf("invalid requirement: %q: unclosed extras", s)
+		}
+		for _, extra := range strings.Split(rest[1:end], ",") {
+			if extra = strings.TrimSpace(extra); extra != "" {
+				req.Extras = append(req.Extras, extra)
+			}
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+
+	if strings.HasPrefix(rest, "@") {
+		// URL 안에 ; 가 들어갈 수 있으므로 marker 는 공백 뒤의 ; 로만 구분합니다.
+		rest = strings.TrimSpace(rest[1:])
+		url, marker, _ := strings.Cut(rest, " ;")
+		req.URL = strings.TrimSpace(url)
+		req.Marker = strings.TrimSpace(marker)
+		if req.URL == "" {
+			return req, fmt.Errorf("invalid requirement: %q: empty url", s)
+		}
+		return req, nil
+	}
+
+	spec, marker, _ := strings.Cut(rest, ";")
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "(") && strings.HasSuffix(spec, ")") {
+		spec = spec[1 : len(spec)-1]
+	}
+	req.Specifier = strings.ReplaceAll(spec, " ", "")
+	req.Marker = strings.TrimSpace(marker)
+	return req, nil
+}
+
+// String 은 requirements.txt 에 쓸 수 있는 형태로 되돌립니다.
+func (r Requirement) String() string {
+	var b strings.Builder
+	b.WriteString(r.Name)
+	if len(r.Extras) > 0 {
+		b.WriteString("[" + strings.Join(r.Extras, ",") + "]")
+	}
+	if r.URL != "" {
+		b.WriteString(" @ " + r.URL)
+		if r.Marker != "" {
+			b.WriteString(" ")
+		}
+	} else {
+		b.WriteString(r.Specifier)
+	}
+	if r.Marker != "" {
+		b.WriteString("; " + r.Marker)
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package dist
+
+import (
+	"strings"
+)
+
+var specifierOps = []string{"===", "~=", "==", "!=", "<=", ">=", "<", ">"}
+
+// MatchSpecifier 는 version 이 ">=1.0,<2" 같은 specifier 를 만족하는지 확인합니다.
+// 빈 specifier 는 모든 버전을 허용합니다.
+func MatchSpecifier(version, specifier string) bool {
+	v, ok := ParseVersion(version)
+	for _, clause := range strings.Split(specifier, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		op, target := splitSpecifierClause(clause)
+		if op == "===" {
+			if version != target {
+				return false
+			}
+			continue
+		}
+		if !ok || !matchClause(v, op, target) {
+			return false
+		}
+	}
+	return true
+}
+
+func splitSpecifierClause(clause string) (string, string) {
+	for _, op := range specifierOps {
+		if strings.HasPrefix(clause, op) {
+			return op, strings.TrimSpace(clause[len(op):])
+		}
+	}
+	return "==", clause
+}
+
+func matchClause(v Version, op, target string) bool {
+	if strings.HasSuffix(target, ".*") {
+		prefix, ok := ParseVersion(strings.TrimSuffix(target, ".*"))
+		if !ok {
+			return false
+		}
+		matched := v.Epoch == prefix.Epoch && hasReleasePrefix(v.Release, prefix.Release)
+		switch op {
+		case "==":
+			return matched
+		case "!=":
+			return !matched
+		}
+		return false
+	}
+
+	t, ok := ParseVersion(target)
+	if !ok {
+		return false
+	}
+	// specifier 에 local 버전이 없으면 설치된 버전의 local 은 무시합니다.
+	if t.Local == "" {
+		v.Local = ""
+	}
+	c := v.Compare(t)
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "~=":
+		// ~=2.2.1 은 >=2.2.1, ==2.2.* 와 같습니다.
+		if len(t.Release) < 2 {
+			return false
+		}
+		return c >= 0 && v.Epoch == t.Epoch && hasReleasePrefix(v.Release, t.Release[:len(t.Release)-1])
+	}
+	return false
+}
+
+func hasReleasePrefix(release, prefix []int) bool {
+	for i, n := range prefix {
+		var x int
+		if i < len(release) {
+			x = release[i]
+		}
+		if x != n {
+			return false
+		}
+	}
+	return true
+}
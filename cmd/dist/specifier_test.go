@@ -0,0 +1,39 @@
+package dist
+
+import "testing"
+
+func TestMatchSpecifier(t *testing.T) {
+	tests := []struct {
+		version   string
+		specifier string
+		want      bool
+	}{
+		{"1.0", "", true},
+		{"2.31.0", ">=2", true},
+		{"1.9", ">=2", false},
+		{"2.0", ">=1.0,<2", false},
+		{"1.5", ">=1.0,<2", true},
+		{"1.5", ">=1.0, <2", true},
+		{"1.0", "==1.0.0", true},
+		{"1.0.1", "==1.0", false},
+		{"1.4.2", "==1.4.*", true},
+		{"1.5.0", "==1.4.*", false},
+		{"1.4.2", "!=1.4.*", false},
+		{"1.3", "!=1.4", true},
+		{"2.2.1", "~=2.2", true},
+		{"3.0", "~=2.2", false},
+		{"1.4.9", "~=1.4.5", true},
+		{"1.5.0", "~=1.4.5", false},
+		{"1.4.4", "~=1.4.5", false},
+		{"1.0", "<=1.0", true},
+		{"1.0", ">1.0", false},
+		{"2.0rc1", ">=1.0", true},
+		{"foo", "===foo", true},
+		{"foo", ">=1.0", false},
+	}
+	for _, tt := range tests {
+		if got := MatchSpecifier(tt.version, tt.specifier); got != tt.want {
+			t.Errorf("MatchSpecifier(%q, %q) = %v, want %v", tt.version, tt.specifier, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package dist
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version 은 PEP 440 버전입니다. (1!2.0.1rc1.post2.dev3+local)
+type Version struct {
+	Epoch   int
+	Release []int
+	Pre     string // a, b, rc
+	PreNum  int
+	Post    int // -1 이면 없음
+	Dev     int // -1 이면 없음
+	Local   string
+}
+
+var versionRe = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?(dev)[-_.]?(\d*))?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+var localSeparators = strings.NewReplacer("-", ".", "_", ".")
+
+// ParseVersion 은 PEP 440 버전 문자열을 읽습니다.
+func ParseVersion(s string) (Version, bool) {
+	m := versionRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return Version{}, false
+	}
+	// local 구분자(-, _)는 . 으로 맞춰야 1.0+a-1 과 1.0+a.1 이 같게 비교됩니다.
+	v := Version{Post: -1, Dev: -1, Local: localSeparators.Replace(m[10])}
+	v.Epoch, _ = strconv.Atoi(m[1])
+	for _, part := range strings.Split(m[2], ".") {
+		n, _ := strconv.Atoi(part)
+		v.Release = append(v.Release, n)
+	}
+	if m[3] != "" {
+		switch m[3] {
+		case "alpha":
+			v.Pre = "a"
+		case "beta":
+			v.Pre = "b"
+		case "c", "pre", "preview":
+			v.Pre = "rc"
+		default:
+			v.Pre = m[3]
+		}
+		v.PreNum, _ = strconv.Atoi(m[4])
+	}
+	switch {
+	case m[5] != "":
+		v.Post, _ = strconv.Atoi(m[5])
+	case m[6] != "":
+		v.Post, _ = strconv.Atoi(m[7])
+	}
+	if m[8] != "" {
+		v.Dev, _ = strconv.Atoi(m[9])
+	}
+	return v, true
+}
+
+// String 은 PEP 440 정규화된 버전입니다. (1.0-RC1 -> 1.0rc1, 1.0-1 -> 1.0.post1)
+func (v Version) String() string {
+	var b strings.Builder
+	if v.Epoch != 0 {
+		b.WriteString(strconv.Itoa(v.Epoch) + "!")
+	}
+	for i, n := range v.Release {
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(strconv.Itoa(n))
+	}
+	if v.Pre != "" {
+		b.WriteString(v.Pre + strconv.Itoa(v.PreNum))
+	}
+	if v.Post >= 0 {
+		b.WriteString(".post" + strconv.Itoa(v.Post))
+	}
+	if v.Dev >= 0 {
+		b.WriteString(".dev" + strconv.Itoa(v.Dev))
+	}
+	if v.Local != "" {
+		b.WriteString("+" + localSeparators.Replace(v.Local))
+	}
+	return b.String()
+}
+
+// IsPrerelease 는 a/b/rc/dev 버전인지 확인합니다.
+func (v Version) IsPrerelease() bool {
+	return v.Pre != "" || v.Dev >= 0
+}
+
+// CompareVersions 는 PEP 440 순서로 a, b 를 비교합니다.
+// 둘 중 하나라도 버전 형식이 아니면 문자열로 비교합니다.
+func CompareVersions(a, b string) int {
+	va, okA := ParseVersion(a)
+	vb, okB := ParseVersion(b)
+	if !okA || !okB {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
+
+func (v Version) Compare(o Version) int {
+	if c := compareInt(v.Epoch, o.Epoch); c != 0 {
+		return c
+	}
+	if c := compareRelease(v.Release, o.Release); c != 0 {
+		return c
+	}
+	if c := compareInt(v.preKey(), o.preKey()); c != 0 {
+		return c
+	}
+	if v.Pre != "" && v.Pre == o.Pre {
+		if c := compareInt(v.PreNum, o.PreNum); c != 0 {
+			return c
+		}
+	}
+	if c := compareInt(v.Post, o.Post); c != 0 {
+		return c
+	}
+	// dev 가 없는 쪽이 더 큽니다.
+	if c := compareInt(devKey(v.Dev), devKey(o.Dev)); c != 0 {
+		return c
+	}
+	return strings.Compare(v.Local, o.Local)
+}
+
+// preKey 는 dev < a < b < rc < (정식) 순서를 숫자로 표현합니다.
+func (v Version) preKey() int {
+	switch v.Pre {
+	case "a":
+		return 1
+	case "b":
+		return 2
+	case "rc":
+		return 3
+	}
+	if v.Dev >= 0 && v.Post < 0 {
+		// 1.0.dev1 은 1.0a1 보다 작습니다.
+		return 0
+	}
+	return 4
+}
+
+func devKey(dev int) int {
+	if dev < 0 {
+		return int(^uint(0) >> 1)
+	}
+	return dev
+}
+
+func compareRelease(a, b []int) int {
+	n := max(len(a), len(b))
+	for i := 0; i < n; i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if c := compareInt(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
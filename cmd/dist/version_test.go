@@ -0,0 +1,105 @@
+package dist
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1.0", "1.0"},
+		{"v2.31.0", "2.31.0"},
+		{"1.0-RC1", "1.0rc1"},
+		{"1.0alpha2", "1.0a2"},
+		{"1.0.preview1", "1.0rc1"},
+		{"1.0-1", "1.0.post1"},
+		{"1.0.rev3", "1.0.post3"},
+		{"1.0.dev", "1.0.dev0"},
+		{"1!2.0", "1!2.0"},
+		{"1.0+Ubuntu-1", "1.0+ubuntu.1"},
+	}
+	for _, tt := range tests {
+		v, ok := ParseVersion(tt.in)
+		if !ok {
+			t.Errorf("ParseVersion(%q) failed", tt.in)
+			continue
+		}
+		if got := v.String(); got != tt.want {
+			t.Errorf("ParseVersion(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1.0-foo", "1..0"} {
+		if _, ok := ParseVersion(in); ok {
+			t.Errorf("ParseVersion(%q) succeeded, want failure", in)
+		}
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"01.002.0", "1.2.0"},
+		{"1.0c1", "1.0rc1"},
+		{"1.0.0a", "1.0.0a0"},
+		{"1.0_beta_3", "1.0b3"},
+		{"1.0-post2", "1.0.post2"},
+		{"1.0.POST", "1.0.post0"},
+		{"1.0r", "1.0.post0"},
+		{"1.0b2.post345.dev456", "1.0b2.post345.dev456"},
+		{"1.0-dev-7", "1.0.dev7"},
+		{"0!1.0", "1.0"},
+		{" 2.0 ", "2.0"},
+		{"1.0+abc_DEF-7", "1.0+abc.def.7"},
+	}
+	for _, tt := range tests {
+		v, ok := ParseVersion(tt.in)
+		if !ok {
+			t.Errorf("ParseVersion(%q) failed", tt.in)
+			continue
+		}
+		if got := v.String(); got != tt.want {
+			t.Errorf("ParseVersion(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+		// 정규화한 버전은 다시 읽어도 같아야 합니다.
+		if again, ok := ParseVersion(v.String()); !ok || again.String() != tt.want || again.Compare(v) != 0 {
+			t.Errorf("ParseVersion(%q) does not round-trip", v.String())
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	// 오름차순
+	ordered := []string{
+		"1.0.dev0", "1.0a1", "1.0a2.dev1", "1.0a2", "1.0b1", "1.0rc1",
+		"1.0", "1.0+local", "1.0.post1.dev1", "1.0.post1", "1.1", "2.0", "1!0.5",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, b := ordered[i], ordered[i+1]
+		if c := CompareVersions(a, b); c >= 0 {
+			t.Errorf("CompareVersions(%q, %q) = %d, want < 0", a, b, c)
+		}
+		if c := CompareVersions(b, a); c <= 0 {
+			t.Errorf("CompareVersions(%q, %q) = %d, want > 0", b, a, c)
+		}
+	}
+
+	equal := [][2]string{{"1.0", "1.0.0"}, {"1.0RC1", "1.0rc1"}, {"v1.2", "1.2"}}
+	for _, pair := range equal {
+		if c := CompareVersions(pair[0], pair[1]); c != 0 {
+			t.Errorf("CompareVersions(%q, %q) = %d, want 0", pair[0], pair[1], c)
+		}
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	tests := map[string]bool{"1.0": false, "1.0a1": true, "1.0rc2": true, "1.0.dev3": true, "1.0.post1": false}
+	for in, want := range tests {
+		v, _ := ParseVersion(in)
+		if got := v.IsPrerelease(); got != want {
+			t.Errorf("ParseVersion(%q).IsPrerelease() = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/modfile"
+)
+
+// dotenvFileName 은 pigo run 이 프로젝트 루트에서 자동으로 읽는 파일입니다.
+const dotenvFileName = ".env"
+
+// envVar 는 NAME=value 하나입니다.
+type envVar struct {
+	Name  string
+	Value string
+}
+
+// readDotenv 는 path 의 .env 파일을 읽습니다.
+// ${NAME} 은 환경 변수, 이 파일에서 앞서 읽은 변수, lookup 순서로 찾아 펼칩니다.
+func readDotenv(path string, lookup func(string) (string, bool)) ([]envVar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := parseDotenv(string(data), lookup)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// parseDotenv 는 .env 형식을 읽습니다.
+//
+//	# 주석
+//	export NAME=value
+//	NAME="값 안의 \n 과 ${OTHER} 는 펼칩니다"
+//	NAME='그대로'
+//	NAME=value # 따옴표 없는 값 뒤의 주석
+func parseDotenv(content string, lookup func(string) (string, bool)) ([]envVar, error) {
+	var vars []envVar
+	local := make(map[string]string)
+	expand := func(s string) string {
+		return os.Expand(s, func(name string) string {
+			// 이미 설정된 환경 변수는 덮어쓰지 않으므로 펼칠 때도 먼저 씁니다.
+			if v, ok := os.LookupEnv(name); ok {
+				return v
+			}
+			if v, ok := local[name]; ok {
+				return v
+			}
+			v, _ := lookup(name)
+			return v
+		})
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("line %d: expected NAME=value", i+1)
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, "'"):
+			// 작은따옴표 안은 여러 줄이어도 그대로 씁니다.
+			end := strings.Index(value[1:], "'")
+			for end < 0 && i+1 < len(lines) {
+				i++
+				value += "\n" + lines[i]
+				end = strings.Index(value[1:], "'")
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quote", i+1)
+			}
+			value = value[1 : end+1]
+		case strings.HasPrefix(value, `"`):
+			end := closingQuote(value)
+			for end < 0 && i+1 < len(lines) {
+				i++
+				value += "\n" + lines[i]
+				end = closingQuote(value)
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quote", i+1)
+			}
+			value = expand(unescapeDotenv(value[1:end]))
+		default:
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = strings.TrimSpace(value[:j])
+			}
+			value = expand(value)
+		}
+		local[name] = value
+		vars = append(vars, envVar{Name: name, Value: value})
+	}
+	return vars, nil
+}
+
+// closingQuote 는 s[0] 의 큰따옴표를 닫는 위치입니다. 없으면 -1 입니다.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeDotenv(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(s)
+}
+
+// runEnvironment 는 pigo run 이 실행하는 프로세스의 환경 변수입니다.
+// pigo.mod 의 env(공통, script 순서), 프로젝트의 .env, --env-file 순서로 읽고 뒤의 값이 이기지만,
+// 이미 설정된 환경 변수는 덮어쓰지 않습니다. activate 처럼 VIRTUAL_ENV 를 설정하고
+// PATH 앞에 .venv 의 실행 파일 디렉토리를 붙이며 PYTHONHOME 은 지웁니다.
+func runEnvironment(script string, envFiles []string) ([]string, error) {
+	mf, err := modfile.Read(projectPath(modfile.FileName))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	lookup := func(name string) (string, bool) {
+		if v, ok := os.LookupEnv(name); ok {
+			return v, true
+		}
+		v, ok := values[name]
+		return v, ok
+	}
+	for _, v := range mf.ScriptEnv(script) {
+		values[v.Name] = os.Expand(v.Value, func(name string) string {
+			v, _ := lookup(name)
+			return v
+		})
+	}
+
+	dotenv := projectPath(dotenvFileName)
+	if _, err := os.Stat(dotenv); err == nil {
+		vars, err := readDotenv(dotenv, lookup)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vars {
+			values[v.Name] = v.Value
+		}
+	}
+	for _, path := range envFiles {
+		vars, err := readDotenv(path, lookup)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vars {
+			values[v.Name] = v.Value
+		}
+	}
+
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if name == "VIRTUAL_ENV" || name == "PATH" || name == "PYTHONHOME" {
+			continue
+		}
+		env = append(env, kv)
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if _, ok := os.LookupEnv(name); !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, name+"="+values[name])
+	}
+
+	path := venvBinDir()
+	if p := os.Getenv("PATH"); p != "" {
+		path += string(filepath.ListSeparator) + p
+	}
+	return append(env, "VIRTUAL_ENV="+venvDir(), "PATH="+path), nil
+}
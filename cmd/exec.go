@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <command> [args...]",
+	Short: "Run a command with the virtual environment activated",
+	Long: `Runs any command with .venv activated: VIRTUAL_ENV is set, the venv bin
+directory comes first in PATH and the environment from pigo.mod and .env is
+loaded, the same as for pigo run. The command is looked up in .venv first.
+
+  pigo exec pytest -x
+  pigo exec -- alembic upgrade head`,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		args = dropSeparator(args)
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			cmd.Help()
+			return
+		}
+		if err := runExec(args[0], args[1:]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+// runExec 는 가상환경을 활성화한 환경에서 name 을 실행합니다.
+func runExec(name string, args []string) error {
+	path, err := venvExecutable(name)
+	if err != nil {
+		return err
+	}
+	env, err := runEnvironment("", nil)
+	if err != nil {
+		return err
+	}
+	c := exec.Command(path, args...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Stdin = os.Stdin
+	c.Env = env
+	return runProcess(context.Background(), c)
+}
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start $SHELL with the virtual environment activated",
+	Long: `Starts your shell ($SHELL) with .venv activated. bash, zsh and fish source the
+activate script after your own rc file, so the prompt shows the venv and
+'deactivate' works; other shells only get VIRTUAL_ENV and PATH.
+Exit the shell to leave the environment.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runShell(); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+// runShell 은 가상환경을 활성화한 사용자의 셸을 실행합니다.
+func runShell() error {
+	if _, err := os.Stat(pythonPath()); err != nil {
+		return fmt.Errorf(".venv not found; run 'pigo init' first")
+	}
+	if os.Getenv("VIRTUAL_ENV") == venvDir() {
+		return fmt.Errorf("already inside %s", venvDir())
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+		if runtime.GOOS == "windows" {
+			shell = os.Getenv("COMSPEC")
+		}
+	}
+	env, err := runEnvironment("", nil)
+	if err != nil {
+		return err
+	}
+
+	activate := filepath.Join(venvBinDir(), "activate")
+	var args []string
+	switch strings.TrimSuffix(filepath.Base(shell), ".exe") {
+	case "bash":
+		rc, err := writeShellRC("pigo-bashrc-*", `[ -f ~/.bashrc ] && . ~/.bashrc
+. `+shellQuote(activate)+"\n")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(rc)
+		args = []string{"--rcfile", rc, "-i"}
+		env = withoutActivation(env)
+	case "zsh":
+		// zsh 는 rc 파일을 고를 수 없으므로 ZDOTDIR 을 임시 디렉토리로 바꾸고 원래 .zshrc 를 읽게 합니다.
+		dir, err := os.MkdirTemp("", "pigo-zsh-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		orig := os.Getenv("ZDOTDIR")
+		if orig == "" {
+			orig, _ = os.UserHomeDir()
+		}
+		rc := "ZDOTDIR=" + shellQuote(orig) + "\n" +
+			`[ -f "$ZDOTDIR/.zshrc" ] && . "$ZDOTDIR/.zshrc"` + "\n" +
+			". " + shellQuote(activate) + "\n"
+		if err := os.WriteFile(filepath.Join(dir, ".zshrc"), []byte(rc), 0644); err != nil {
+			return err
+		}
+		env = append(withoutActivation(env), "ZDOTDIR="+dir)
+	case "fish":
+		args = []string{"-C", "source '" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(activate+".fish") + "'"}
+		env = withoutActivation(env)
+	}
+
+	fmt.Fprintf(os.Stderr, "Activating %s (exit the shell to leave)\n", displayPath(venvDir()))
+	c := exec.Command(shell, args...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Stdin = os.Stdin
+	c.Env = env
+	return runProcess(context.Background(), c)
+}
+
+// withoutActivation 은 env 에서 VIRTUAL_ENV 와 .venv 를 붙인 PATH 를 되돌립니다.
+// activate 스크립트가 직접 설정해야 deactivate 가 원래 PATH 로 돌아갈 수 있습니다.
+func withoutActivation(env []string) []string {
+	var out []string
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "VIRTUAL_ENV=") || strings.HasPrefix(kv, "PATH=") {
+			continue
+		}
+		out = append(out, kv)
+	}
+	return append(out, "PATH="+os.Getenv("PATH"))
+}
+
+// writeShellRC 는 셸에 넘길 임시 rc 파일을 만듭니다.
+func writeShellRC(pattern, content string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// shellQuote 는 s 를 sh 에서 쓸 수 있게 작은따옴표로 감쌉니다.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(shellCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/spf13/cobra"
+)
+
+// graphNode 는 설치돼 있으면 name@version, 아니면 name 입니다.
+func graphNode(installed map[string]*dist.Distribution, name string) string {
+	if d, ok := installed[dist.NormalizeName(name)]; ok {
+		return d.Name + "@" + d.Version
+	}
+	return name
+}
+
+// printGraph 는 requirements.txt 에서 시작하는 의존성 그래프를
+// "from to" 한 줄에 하나씩 출력하고, 마지막에 replace 를 출력합니다.
+func printGraph(root string) error {
+	venvPath := filepath.Join(root, ".venv")
+	dists, err := dist.LoadVenv(venvPath)
+	if err != nil {
+		return fmt.Errorf("reading installed packages: %w", err)
+	}
+	env, err := dist.VenvEnvironment(venvPath)
+	if err != nil {
+		return fmt.Errorf("reading python environment: %w", err)
+	}
+	installed := dist.Index(dists)
+
+	var reqs []dist.Requirement
+	reqPath := filepath.Join(root, "requirements.txt")
+	if _, err := os.Stat(reqPath); err == nil {
+		if reqs, err = readRequirements(reqPath); err != nil {
+			return err
+		}
+	}
+
+	project := filepath.Base(root)
+	seen := make(map[string]bool)
+	edge := func(from, to string) {
+		line := from + " " + to
+		if !seen[line] {
+			seen[line] = true
+			fmt.Println(line)
+		}
+	}
+
+	for _, req := range reqs {
+		if ok, err := dist.EvaluateMarker(req.Marker, env, nil); err == nil && ok {
+			edge(project, graphNode(installed, req.Name))
+		}
+	}
+
+	closure := dist.Closure(installed, reqs, env)
+	names := make([]string, 0, len(closure))
+	for name := range closure {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d, ok := installed[name]
+		if !ok {
+			continue
+		}
+		from := graphNode(installed, name)
+		for _, dep := range d.Requires {
+			if ok, err := dist.EvaluateMarker(dep.Marker, env, closure[name]); err == nil && ok {
+				edge(from, graphNode(installed, dep.Name))
+			}
+		}
+	}
+
+	mf, err := modfile.Read(filepath.Join(root, modfile.FileName))
+	if err != nil {
+		return err
+	}
+	for _, r := range mf.Replace {
+		fmt.Printf("%s => %s\n", graphNode(installed, r.Name), r.Path)
+	}
+	return nil
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Print the dependency graph of the project",
+	Long: `Prints the dependency graph built from requirements.txt and the metadata of the
+packages installed in .venv, one "from to" edge per line. Installed packages are
+shown as name@version. Replacements from pigo.mod are listed as "name@version => path".`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := printGraph(projectRoot()); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRules 는 프로젝트 루트의 .gitignore 규칙입니다.
+// 주석, ! 부정, 끝의 / (디렉토리만), 가운데 / 가 있으면 루트 기준 경로로 비교하는 것까지 지원합니다.
+type ignoreRules struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	glob     string
+	negate   bool
+	dirOnly  bool
+	anchored bool // 루트 기준 경로와 비교 (아니면 파일 이름과 비교)
+}
+
+// readIgnoreRules 는 root/.gitignore 를 읽습니다. 파일이 없으면 규칙이 없습니다.
+func readIgnoreRules(root string) *ignoreRules {
+	r := &ignoreRules{}
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return r
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " ")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate, line = true, line[1:]
+		}
+		line = strings.TrimSuffix(line, "/**")
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly, line = true, strings.TrimSuffix(line, "/")
+		}
+		line = strings.TrimPrefix(line, "**/")
+		if strings.Contains(line, "/") {
+			p.anchored, line = true, strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		p.glob = line
+		r.patterns = append(r.patterns, p)
+	}
+	return r
+}
+
+// match 는 루트 기준 상대경로 rel 이 무시되는지 확인합니다. 뒤의 규칙이 이깁니다.
+func (r *ignoreRules) match(rel string, isDir bool) bool {
+	rel = filepath.ToSlash(rel)
+	ignored := false
+	for _, p := range r.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		name := path.Base(rel)
+		if p.anchored {
+			name = rel
+		}
+		if ok, _ := path.Match(p.glob, name); ok {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/spf13/cobra"
+)
+
+// ImportUsage 는 third-party import 하나의 사용 현황입니다.
+type ImportUsage struct {
+	Import       string       `json:"import"`
+	Requirement  string       `json:"requirement,omitempty"`
+	Distribution string       `json:"distribution,omitempty"`
+	Installed    bool         `json:"installed"`
+	Declared     bool         `json:"declared"`
+	Count        int          `json:"count"`
+	Files        []string     `json:"files"`
+	Sites        []ImportSite `json:"sites"`
+}
+
+// importOwners 는 import 이름 -> 그 모듈을 제공하는 설치된 배포판 map 을 만듭니다.
+func importOwners(dists []*dist.Distribution) map[string]*dist.Distribution {
+	owners := make(map[string]*dist.Distribution)
+	for _, d := range dists {
+		for _, name := range d.ImportNames {
+			if _, exists := owners[name]; !exists {
+				owners[name] = d
+			}
+		}
+	}
+	return owners
+}
+
+// collectImportUsage 는 searchPath 의 third-party import 를 requirement 와 짝지어 줍니다.
+// venv 를 읽을 수 없으면 requirement 이름으로만 짝을 찾습니다.
+func collectImportUsage(searchPath string) ([]ImportUsage, error) {
+	sites, err := scanImports(searchPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var reqs []dist.Requirement
+	reqPath := filepath.Join(searchPath, "requirements.txt")
+	if _, err := os.Stat(reqPath); err == nil {
+		if reqs, err = readRequirements(reqPath); err != nil {
+			return nil, err
+		}
+	}
+	declared := make(map[string]dist.Requirement)
+	var reqNames []string
+	for _, req := range reqs {
+		declared[dist.NormalizeName(req.Name)] = req
+		reqNames = append(reqNames, req.Name)
+	}
+
+	dists, err := dist.LoadVenv(filepath.Join(searchPath, ".venv"))
+	if err != nil {
+		log.Printf("warning: reading installed packages: %v", err)
+	}
+	owners := importOwners(dists)
+	replaced, err := replacements(searchPath)
+	if err != nil {
+		return nil, err
+	}
+	pkgInfoMap := fetchPackageInfo(dist.Index(dists), reqNames, replaced)
+
+	byRoot := make(map[string]*ImportUsage)
+	for module, moduleSites := range sites {
+		root := getRootModule(module)
+		if isStdlibModule(root) {
+			continue
+		}
+		usage, ok := byRoot[root]
+		if !ok {
+			usage = &ImportUsage{Import: root}
+			byRoot[root] = usage
+		}
+		usage.Sites = append(usage.Sites, moduleSites...)
+	}
+
+	var result []ImportUsage
+	for root, usage := range byRoot {
+		if d, ok := owners[root]; ok {
+			usage.Distribution = d.Name
+			usage.Installed = true
+			usage.Requirement = fmt.Sprintf("%s==%s", d.Name, d.Version)
+			if req, ok := declared[dist.NormalizeName(d.Name)]; ok {
+				usage.Requirement = req.String()
+				usage.Declared = true
+			}
+		} else {
+			// 설치되지 않은 패키지는 requirement 이름으로 추정합니다.
+			for _, req := range reqs {
+				if isPackageUsed(req.Name, pkgInfoMap[req.Name], map[string]bool{root: true}) {
+					usage.Distribution = req.Name
+					usage.Requirement = req.String()
+					usage.Declared = true
+					break
+				}
+			}
+		}
+
+		sort.Slice(usage.Sites, func(i, j int) bool {
+			if usage.Sites[i].File != usage.Sites[j].File {
+				return usage.Sites[i].File < usage.Sites[j].File
+			}
+			return usage.Sites[i].Line < usage.Sites[j].Line
+		})
+		usage.Count = len(usage.Sites)
+		for _, site := range usage.Sites {
+			if len(usage.Files) == 0 || usage.Files[len(usage.Files)-1] != site.File {
+				usage.Files = append(usage.Files, site.File)
+			}
+		}
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Import < result[j].Import })
+	return result, nil
+}
+
+var importsCmd = &cobra.Command{
+	Use:   "imports [path]",
+	Short: "Show third-party imports and the requirements that satisfy them",
+	Long: `Scans .py files under path (default '.') and prints every third-party import
+with the requirement that provides it, the files using it, how often it is imported
+and whether it is declared in requirements.txt.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		searchPath := projectRoot()
+		if len(args) > 0 {
+			searchPath = args[0]
+		}
+
+		usages, err := collectImportUsage(searchPath)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if usages == nil {
+				usages = []ImportUsage{}
+			}
+			if err := enc.Encode(usages); err != nil {
+				log.Fatalf("error: %v", err)
+			}
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "IMPORT\tREQUIREMENT\tDECLARED\tCOUNT\tFILES")
+		for _, u := range usages {
+			requirement := u.Requirement
+			if requirement == "" {
+				requirement = "-"
+			}
+			declared := "no"
+			if u.Declared {
+				declared = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", u.Import, requirement, declared, u.Count, strings.Join(u.Files, ", "))
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importsCmd)
+
+	importsCmd.Flags().Bool("json", false, "Print the report as JSON")
+}
@@ -4,10 +4,14 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"os"
-	"os/exec"
+	"path/filepath"
 
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/janghanul090801/pigo/cmd/venv"
 	"github.com/spf13/cobra"
 )
 
@@ -22,15 +26,22 @@ Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		venvCmd := exec.Command("python", "-m", "venv", ".venv")
-		venvCmd.Stdout = os.Stdout
-		venvCmd.Stderr = os.Stderr
+		// 다른 명령처럼 -C 로 옮긴 디렉토리에서 찾은 프로젝트 루트에 만듭니다.
+		mf, err := modfile.Read(projectPath(modfile.FileName))
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		interp, err := findInterpreter(mf.Python)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
 
-		if err := venvCmd.Run(); err != nil {
+		seed, _ := cmd.Flags().GetBool("seed")
+		if err := createVenv(venvDir(), interp, seed, os.Stdout); err != nil {
 			log.Fatalf("error: %v", err)
 		}
 
-		file, err := os.OpenFile("requirements.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		file, err := os.OpenFile(projectPath("requirements.txt"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
 			log.Fatalf("error creating file: %v", err)
 		}
@@ -38,6 +49,25 @@ to quickly create a Cobra application.`,
 	},
 }
 
+// createVenv 는 python -m venv 없이 interp 로 dir 에 가상환경을 만듭니다.
+// seed 가 true 면 캐시된 wheel 로 pip 을 설치하고 out 에 알립니다.
+// pip wheel 을 찾지 못하면 경고만 하고 pip 없는 가상환경을 남깁니다.
+func createVenv(dir string, interp Interpreter, seed bool, out io.Writer) error {
+	if err := venv.Create(dir, venv.Options{Interpreter: interp.Path, Version: interp.Version}); err != nil {
+		return err
+	}
+	if !seed {
+		return nil
+	}
+	wheel, err := venv.FindPipWheel(filepath.Join(pigoCacheDir(), "wheels"), interp.Path)
+	if err != nil {
+		log.Printf("warning: %v; created %s without pip", err, displayPath(dir))
+		return nil
+	}
+	fmt.Fprintf(out, "Seeding %s\n", filepath.Base(wheel))
+	return venv.InstallWheel(dir, interp.Version, wheel)
+}
+
 func init() {
 	rootCmd.AddCommand(initCmd)
 
@@ -50,4 +80,5 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// initCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	initCmd.Flags().Bool("seed", true, "Install pip into the venv from a cached wheel")
 }
@@ -4,15 +4,19 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
-	_const "github.com/janghanul090801/pigo/cmd/const"
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/janghanul090801/pigo/cmd/vcs"
 	"github.com/spf13/cobra"
 )
 
@@ -28,66 +32,714 @@ This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
 	DisableFlagParsing: true,
 	Run: func(cmd *cobra.Command, args []string) {
-		installArgs := append([]string{"install"}, args...)
-		installCmd := exec.Command(_const.PIPPATHWINDOW, installArgs...)
-		installCmd.Stdout = os.Stdout
-		installCmd.Stderr = os.Stderr
-		installCmd.Stdin = os.Stdin
-
-		if err := installCmd.Run(); err != nil {
+		opts, args, err := parseInstallFlags(args)
+		if err != nil {
 			log.Fatalf("error: %v", err)
 		}
+		if packages, ok := cutWorkspacePattern(args); ok {
+			err = forEachMember(func(member string) error {
+				return runInstall(packages, opts)
+			})
+		} else {
+			err = runInstall(args, opts)
+		}
+		if err != nil {
+			fatal(err)
+		}
+	},
+}
+
+// installOptions 는 pip 에 넘기지 않고 pigo 가 직접 처리하는 install 옵션입니다.
+type installOptions struct {
+	Mod    string // "mod"(기본값): 인덱스에서 설치, "vendor": vendor/ 에서만 설치
+	Frozen bool   // pigo.lock 을 바꿔야 하면 실패
+	Bound  string // 버전 없이 설치한 패키지를 기록할 연산자: "=="(--pin) 또는 "~="(--compatible), 없으면 ==
 
-		var targetPackages []string
-		for _, arg := range args {
-			if !strings.HasPrefix(arg, "-") {
-				targetPackages = append(targetPackages, arg)
+	Timeout time.Duration // pip 실행 전체의 제한 시간
+	Out     io.Writer     // pip 의 출력 (nil 이면 stdout)
+}
+
+// parseInstallFlags 는 args 에서 pigo 의 install 옵션을 꺼내고,
+// 나머지(pip 옵션과 패키지)는 순서대로 돌려줍니다.
+func parseInstallFlags(args []string) (installOptions, []string, error) {
+	opts := installOptions{Mod: "mod"}
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if d, next, ok, err := cutTimeoutFlag(args, i); ok {
+			if err != nil {
+				return opts, nil, err
+			}
+			opts.Timeout, i = d, next
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "-mod", "--mod":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return opts, nil, fmt.Errorf("flag needs an argument: %s", name)
+				}
+				i++
+				value = args[i]
+			}
+			if value != "mod" && value != "vendor" {
+				return opts, nil, fmt.Errorf("invalid -mod=%s: must be mod or vendor", value)
 			}
+			opts.Mod = value
+		case "--frozen":
+			opts.Frozen = true
+		case "--pin":
+			opts.Bound = "=="
+		case "--compatible":
+			opts.Bound = "~="
+		case "--offline":
+			// 플래그 파싱을 끈 명령이므로 서브커맨드 뒤의 전역 플래그도 여기서 읽습니다.
+			offline = true
+		default:
+			rest = append(rest, arg)
 		}
+	}
+	return opts, rest, nil
+}
 
-		if len(targetPackages) == 0 {
-			return
+// runInstall 은 현재 프로젝트의 venv 에 패키지를 설치하고 requirements.txt 에 기록합니다.
+// 설치할 패키지가 없으면 requirements.txt 를 pigo.lock 의 버전으로 설치하고,
+// requirements.txt 없이 pigo.lock 만 있으면 lock 에 적힌 패키지를 그대로 설치합니다.
+// -r file 로 설치한 requirements 파일의 항목은 requirements.txt 로 가져옵니다.
+// pigo.mod 에서 replace 된 패키지는 로컬 경로에서 editable(-e) 로 설치하고,
+// git 의존성은 커밋으로 고정해서 설치한 뒤 pigo.lock 에 기록합니다.
+// --frozen 이면 pigo.lock 과 requirements.txt 를 바꿔야 할 때 pip 을 실행하기 전에 실패합니다.
+// -mod=vendor 이면 vendor/ 에서만 설치하고 requirements.txt 와 pigo.lock 은 그대로 둡니다.
+func runInstall(args []string, opts installOptions) error {
+	ctx, cancel := timeoutContext(opts.Timeout)
+	defer cancel()
+	pipOut := opts.Out
+	if pipOut == nil {
+		pipOut = os.Stdout
+	}
+	if opts.Mod == "vendor" {
+		return installVendored(ctx, args)
+	}
+	reqPath := projectPath("requirements.txt")
+	replaced, err := replacements(projectRoot())
+	if err != nil {
+		return err
+	}
+	lock, err := readLock()
+	if err != nil {
+		return err
+	}
+	var reqs []dist.Requirement
+	if _, err := os.Stat(reqPath); err == nil {
+		if reqs, err = readRequirements(reqPath); err != nil {
+			return err
 		}
+	}
 
-		showArgs := append([]string{"show"}, targetPackages...)
-		showCmd := exec.Command(_const.PIPPATHWINDOW, showArgs...)
+	packages, reqFiles := splitInstallArgs(args)
+	var targetPackages []string
+	for _, arg := range packages {
+		if !isVCSArg(arg) {
+			targetPackages = append(targetPackages, arg)
+		}
+	}
 
-		var out bytes.Buffer
-		showCmd.Stdout = &out
-		showCmd.Stderr = os.Stderr
+	if offlineMode() {
+		if err := checkOfflineInstall(args, reqs, lock, replaced); err != nil {
+			return err
+		}
+	}
+	if opts.Frozen {
+		if err := checkFrozenLock(reqs, lock, replaced); err != nil {
+			return err
+		}
+		if err := checkFrozenArgs(packages, reqFiles, reqPath, reqs, lock, replaced); err != nil {
+			return err
+		}
+	}
 
-		if err := showCmd.Run(); err != nil {
-			log.Printf("warning: failed to get package info for requirements.txt: %v", err)
+	pipArgs, vcsTargets, err := resolveVCSArgs(replaceInstallArgs(args, replaced), reqs, lock)
+	if err != nil {
+		return err
+	}
+	var pins []vcs.Ref
+	for _, t := range vcsTargets {
+		pins = append(pins, t.Pinned)
+	}
+	// -r 로 넘긴 파일도 replace 와 git 커밋 고정을 적용한 파일로 바꿉니다.
+	for i, arg := range pipArgs {
+		if i == 0 || !isRequirementFlag(pipArgs[i-1]) {
+			continue
+		}
+		path, filePins, cleanup, err := installRequirementsFile(arg, replaced, lock)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		pipArgs[i] = path
+		pins = append(pins, filePins...)
+	}
+	installArgs := []string{"install"}
+	if offlineMode() {
+		installArgs = append(installArgs, offlinePipArgs()...)
+	}
+	installArgs = append(installArgs, pipArgs...)
+	if !hasPackageArgs(args) {
+		if _, err := os.Stat(reqPath); err != nil {
+			return installLocked(args, pipOut)
+		}
+		path, reqPins, cleanup, err := installRequirementsFile(reqPath, replaced, lock)
+		if err != nil {
+			return err
 		}
+		defer cleanup()
+		pins = append(pins, reqPins...)
+		installArgs = append(installArgs, "-r", path)
 
-		file, err := os.OpenFile("requirements.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		constraints, cleanup, err := lockConstraintsFile(lock, reqs, replaced)
 		if err != nil {
-			log.Fatalf("error creating file: %v", err)
+			return err
 		}
-		defer file.Close()
+		defer cleanup()
+		if constraints != "" {
+			installArgs = append(installArgs, "-c", constraints)
+		}
+	}
+
+	stale, err := staleVCSPackages(pins)
+	if err != nil {
+		return err
+	}
+	if len(stale) > 0 {
+		uninstallCmd := exec.Command(pipPath(), append([]string{"uninstall", "-y"}, stale...)...)
+		uninstallCmd.Stdout = pipOut
+		uninstallCmd.Stderr = os.Stderr
+		if err := runProcess(ctx, uninstallCmd); err != nil {
+			return err
+		}
+	}
+
+	installCmd := exec.Command(pipPath(), installArgs...)
+	installCmd.Stdout = pipOut
+	installCmd.Stderr = os.Stderr
+	installCmd.Stdin = os.Stdin
+	if offlineMode() {
+		installCmd.Env = offlineEnv()
+	}
 
-		scanner := bufio.NewScanner(&out)
-		var currentName, currentVersion string
+	if err := runProcess(ctx, installCmd); err != nil {
+		return err
+	}
 
-		for scanner.Scan() {
-			line := scanner.Text()
+	dists, err := dist.LoadVenv(venvDir())
+	if err != nil {
+		return err
+	}
+	reqFile, err := modfile.ReadRequirements(reqPath)
+	if err != nil {
+		return err
+	}
+	for _, file := range reqFiles {
+		if sameFile(file, reqPath) {
+			continue
+		}
+		imported, err := importRequirements(reqFile, file)
+		if err != nil {
+			return err
+		}
+		targetPackages = append(targetPackages, imported...)
+	}
+	recordVCSTargets(reqFile, dists, vcsTargets)
+	recordPackages(reqFile, dist.Index(dists), targetPackages, opts.Bound)
 
-			if strings.HasPrefix(line, "Name: ") {
-				currentName = strings.TrimSpace(strings.TrimPrefix(line, "Name: "))
-			} else if strings.HasPrefix(line, "Version: ") {
-				currentVersion = strings.TrimSpace(strings.TrimPrefix(line, "Version: "))
+	newLock, err := buildLock(reqFile.List())
+	if err != nil {
+		return err
+	}
+	if opts.Frozen {
+		if !bytes.Equal(newLock.Format(), lock.Format()) {
+			return fmt.Errorf("%s needs to be updated but --frozen was given; run pigo install without --frozen", modfile.LockFileName)
+		}
+		return nil
+	}
+	if len(vcsTargets) > 0 || len(targetPackages) > 0 {
+		if err := reqFile.Write(reqPath); err != nil {
+			return err
+		}
+	}
+	return newLock.Write(lockPath())
+}
+
+// pipValueFlags 는 값을 다음 인자로 받는 pip install 옵션입니다.
+var pipValueFlags = map[string]bool{
+	"-r": true, "--requirement": true, "-c": true, "--constraint": true,
+	"-e": true, "--editable": true, "-t": true, "--target": true,
+	"-i": true, "--index-url": true, "--extra-index-url": true,
+	"-f": true, "--find-links": true, "--src": true, "--root": true, "--prefix": true,
+	"--platform": true, "--python-version": true, "--implementation": true, "--abi": true,
+	"--upgrade-strategy": true, "--no-binary": true, "--only-binary": true,
+	"--progress-bar": true, "--trusted-host": true, "--proxy": true, "--cache-dir": true,
+}
+
+// isRequirementFlag 는 arg 가 값을 다음 인자로 받는 -r 옵션인지 확인합니다.
+func isRequirementFlag(arg string) bool {
+	return arg == "-r" || arg == "--requirement"
+}
+
+// splitInstallArgs 는 install 인자에서 설치할 패키지와 -r 로 넘긴 requirements 파일을 꺼냅니다.
+// 다른 옵션과 그 값은 건너뜁니다.
+func splitInstallArgs(args []string) (packages, reqFiles []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			packages = append(packages, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !hasValue && pipValueFlags[name] && i+1 < len(args) {
+			i++
+			value, hasValue = args[i], true
+		} else if !hasValue && strings.HasPrefix(arg, "-r") && len(arg) > 2 {
+			name, value, hasValue = "-r", arg[2:], true
+		}
+		if hasValue && isRequirementFlag(name) {
+			reqFiles = append(reqFiles, value)
+		}
+	}
+	return packages, reqFiles
+}
+
+// importRequirements 는 requirements 파일 path 의 항목을 reqFile 로 가져옵니다.
+// URL 로 적힌 항목은 그대로 쓰고, 나머지는 recordPackages 로 기록할 수 있게 돌려줍니다.
+func importRequirements(reqFile *modfile.Requirements, path string) ([]string, error) {
+	src, err := modfile.ReadRequirements(path)
+	if err != nil {
+		return nil, err
+	}
+	var packages []string
+	for _, req := range src.List() {
+		if req.URL != "" {
+			reqFile.SetLine(req.Name, req.String())
+			continue
+		}
+		packages = append(packages, req.String())
+	}
+	return packages, nil
+}
+
+// sameFile 은 a 와 b 가 같은 파일인지 확인합니다.
+func sameFile(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(ai, bi)
+}
+
+// lockConstraintsFile 은 pigo.lock 의 버전을 pip constraints 파일(name==version)로 만듭니다.
+// git, replace 패키지와 requirements.txt 의 조건을 만족하지 않는 버전은 넣지 않으므로
+// requirements.txt 를 고치면 해당 패키지만 새로 해석됩니다. 넣을 것이 없으면 "" 입니다.
+func lockConstraintsFile(lock *modfile.LockFile, reqs []dist.Requirement, replaced map[string]string) (string, func(), error) {
+	noop := func() {}
+	specs := make(map[string]string)
+	for _, req := range reqs {
+		specs[dist.NormalizeName(req.Name)] = req.Specifier
+	}
+	var lines []string
+	for _, p := range lock.Packages {
+		name := dist.NormalizeName(p.Name)
+		if p.Source != "" || replaced[name] != "" {
+			continue
+		}
+		if spec := specs[name]; spec != "" && !dist.MatchSpecifier(p.Version, spec) {
+			continue
+		}
+		lines = append(lines, p.Name+"=="+p.Version)
+	}
+	if len(lines) == 0 {
+		return "", noop, nil
+	}
+
+	tmp, err := os.CreateTemp("", "pigo-constraints-*.txt")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+	if _, err := tmp.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", noop, err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return tmp.Name(), cleanup, nil
+}
+
+// installLocked 는 requirements.txt 가 없을 때 pigo.lock 의 패키지를 설치합니다. (pigo sync 와 같지만
+// lock 에 없는 패키지는 지우지 않습니다) 둘 다 없거나 적용할 곳이 없는 pip 옵션을 주면 실패합니다.
+func installLocked(args []string, out io.Writer) error {
+	if _, err := os.Stat(lockPath()); err != nil {
+		return fmt.Errorf("nothing to install: no packages given and neither requirements.txt nor %s exists", modfile.LockFileName)
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("pip options %s need packages or requirements.txt; %s is installed as locked", strings.Join(args, " "), modfile.LockFileName)
+	}
+	return runSync(syncOptions{Out: out})
+}
+
+// checkFrozenLock 은 requirements.txt 의 모든 항목이 pigo.lock 에 고정되어 있는지 확인합니다.
+func checkFrozenLock(reqs []dist.Requirement, lock *modfile.LockFile, replaced map[string]string) error {
+	var missing []string
+	for _, req := range reqs {
+		p, ok := lock.Find(req.Name)
+		switch {
+		case !ok:
+			missing = append(missing, req.Name+" is not in "+modfile.LockFileName)
+		case req.URL == "" && req.Specifier != "" && replaced[dist.NormalizeName(req.Name)] == "" && !dist.MatchSpecifier(p.Version, req.Specifier):
+			missing = append(missing, fmt.Sprintf("%s %s does not satisfy %s", p.Name, p.Version, req.String()))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s is out of date with requirements.txt (--frozen):\n\t%s", modfile.LockFileName, strings.Join(missing, "\n\t"))
+	}
+	return nil
+}
+
+// checkFrozenArgs 는 --frozen 으로 지정한 패키지와 -r 파일의 항목이 이미 requirements.txt 와
+// pigo.lock 에 같은 조건으로 있는지 pip 을 실행하기 전에 확인합니다.
+func checkFrozenArgs(packages, reqFiles []string, reqPath string, reqs []dist.Requirement, lock *modfile.LockFile, replaced map[string]string) error {
+	var lines []string
+	lines = append(lines, packages...)
+	for _, file := range reqFiles {
+		if sameFile(file, reqPath) {
+			continue
+		}
+		fileReqs, err := readRequirements(file)
+		if err != nil {
+			return err
+		}
+		for _, req := range fileReqs {
+			lines = append(lines, req.String())
+		}
+	}
 
-				if currentName != "" && currentVersion != "" {
-					_, err := file.WriteString(fmt.Sprintf("%s==%s\n", currentName, currentVersion))
-					if err != nil {
-						log.Printf("error writing to file: %v", err)
-					}
-					currentName = ""
-					currentVersion = ""
+	required := make(map[string]bool)
+	for _, req := range reqs {
+		required[dist.NormalizeName(req.Name)] = true
+	}
+	var missing []string
+	for _, line := range lines {
+		name, ref, spec, ok := frozenTarget(line)
+		if !ok {
+			missing = append(missing, line+" would change "+modfile.LockFileName)
+			continue
+		}
+		p, locked := lock.Find(name)
+		switch {
+		case !required[dist.NormalizeName(name)] || !locked:
+			missing = append(missing, line+" is not in requirements.txt and "+modfile.LockFileName)
+		case replaced[dist.NormalizeName(name)] != "":
+		case ref != nil:
+			if !frozenCommit(*ref, p) {
+				missing = append(missing, fmt.Sprintf("%s is locked to %s", line, p.Source))
+			}
+		case spec != "" && !dist.MatchSpecifier(p.Version, spec):
+			missing = append(missing, fmt.Sprintf("%s %s does not satisfy %s", p.Name, p.Version, line))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s would need to be updated (--frozen):\n\t%s", modfile.LockFileName, strings.Join(missing, "\n\t"))
+	}
+	return nil
+}
+
+// frozenTarget 은 install 인자에서 패키지 이름과 git ref 또는 버전 조건을 꺼냅니다.
+func frozenTarget(arg string) (name string, ref *vcs.Ref, spec string, ok bool) {
+	if vcs.IsURL(arg) {
+		r, err := vcs.ParseURL(arg)
+		if err != nil || r.Egg() == "" {
+			return "", nil, "", false
+		}
+		return r.Egg(), &r, "", true
+	}
+	req, ok := modfile.ParseRequirementLine(arg)
+	if !ok {
+		return "", nil, "", false
+	}
+	if req.URL != "" {
+		r, err := vcs.ParseURL(req.URL)
+		if err != nil {
+			return "", nil, "", false
+		}
+		return req.Name, &r, "", true
+	}
+	return req.Name, nil, req.Specifier, true
+}
+
+// frozenCommit 은 ref 가 pigo.lock 에 고정된 커밋과 같은 저장소, 같은 커밋을 가리키는지 확인합니다.
+// 브랜치나 태그는 다시 고정해야 하므로 커밋 해시나 pseudo-version 만 받습니다.
+func frozenCommit(ref vcs.Ref, p modfile.LockedPackage) bool {
+	locked, err := vcs.ParseURL(p.Source)
+	if err != nil || locked.Repo != ref.Repo {
+		return false
+	}
+	want, isCommit := vcs.CommitPrefix(ref.Rev)
+	return isCommit && strings.HasPrefix(locked.Rev, want)
+}
+
+// hasPackageArgs 는 args 에 설치할 패키지나 그것을 지정하는 pip 옵션(-r, -e)이 있는지 확인합니다.
+func hasPackageArgs(args []string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return true
+		}
+		switch strings.SplitN(arg, "=", 2)[0] {
+		case "-r", "--requirement", "-e", "--editable":
+			return true
+		}
+	}
+	return false
+}
+
+// checkOfflineInstall 은 네트워크 없이 설치할 수 있는지 pip 을 실행하기 전에 확인하고,
+// 로컬에 없는 패키지를 모두 모아 에러로 돌려줍니다.
+// 패키지를 지정하지 않으면 pigo.lock (없으면 requirements.txt) 의 항목을, -r 로 넘긴 파일은 그 안의 항목을 확인합니다.
+func checkOfflineInstall(args []string, reqs []dist.Requirement, lock *modfile.LockFile, replaced map[string]string) error {
+	c := newOfflineChecker(replaced)
+	if !hasPackageArgs(args) {
+		if len(lock.Packages) > 0 {
+			for _, p := range lock.Packages {
+				c.checkLocked(p)
+			}
+		} else {
+			for _, req := range reqs {
+				c.checkRequirement(req)
+			}
+		}
+		return c.err()
+	}
+	packages, reqFiles := splitInstallArgs(args)
+	for _, arg := range packages {
+		if vcs.IsURL(arg) {
+			ref, err := vcs.ParseURL(arg)
+			if err != nil {
+				return err
+			}
+			c.checkRequirement(dist.Requirement{Name: ref.Egg(), URL: arg})
+			continue
+		}
+		req, ok := modfile.ParseRequirementLine(arg)
+		if !ok {
+			continue
+		}
+		if req.URL != "" && !strings.Contains(req.URL, "://") {
+			// pkg@<rev>
+			if source, ok := vcsSource(req.Name, reqs, lock); ok {
+				source.Rev = req.URL
+				req.URL = source.String()
+			}
+		}
+		c.checkRequirement(req)
+	}
+	for _, file := range reqFiles {
+		fileReqs, err := readRequirements(file)
+		if err != nil {
+			return err
+		}
+		for _, req := range fileReqs {
+			c.checkRequirement(req)
+		}
+	}
+	return c.err()
+}
+
+// recordPackages 는 설치한 패키지를 requirements.txt 편집기에 기록합니다.
+// 사용자가 적은 조건(django>=4,<5), extras, marker 는 그대로 기록하고, 정확한 버전은 pigo.lock 에 남깁니다.
+// 버전 없이 설치한 패키지는 설치된 버전으로 bound(== 또는 ~=) 조건을 적지만, bound 를 지정하지
+// 않았고 이미 있는 줄에 설치된 버전을 만족하는 조건(>=2.0 등)이 적혀 있으면 그대로 둡니다.
+// 이름은 PEP 503 으로 비교해서 이미 있는 줄을 바꿉니다.
+func recordPackages(reqs *modfile.Requirements, installed map[string]*dist.Distribution, targetPackages []string, bound string) {
+	for _, arg := range targetPackages {
+		req, ok := modfile.ParseRequirementLine(arg)
+		if !ok {
+			continue
+		}
+		d, ok := installed[dist.NormalizeName(req.Name)]
+		if !ok {
+			log.Printf("warning: %s is not installed; not recorded in requirements.txt", req.Name)
+			continue
+		}
+		old, exists := reqs.Find(req.Name)
+		rec := req
+		rec.Name = d.Name
+		if exists {
+			rec.Name = old.Name
+		}
+		if req.Specifier != "" || req.URL != "" {
+			reqs.Set(rec)
+			continue
+		}
+		if bound == "" && exists && old.URL == "" && isUserSpecifier(old.Specifier) && dist.MatchSpecifier(d.Version, old.Specifier) {
+			continue
+		}
+
+		rec.Specifier = boundSpecifier(bound, d.Version)
+		if exists {
+			if len(rec.Extras) == 0 {
+				rec.Extras = old.Extras
+			}
+			if rec.Marker == "" {
+				rec.Marker = old.Marker
+			}
+		}
+		reqs.Set(rec)
+	}
+}
+
+// boundSpecifier 는 설치된 version 으로 bound 조건을 만듭니다.
+// ~= 는 release 의 앞 두 자리를 써서 2.31.0 이면 ~=2.31 (>=2.31, <3) 입니다.
+func boundSpecifier(bound, version string) string {
+	if bound != "~=" || strings.Contains(version, "!") {
+		return "==" + version
+	}
+	release := version
+	if i := strings.IndexFunc(release, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); i >= 0 {
+		release = release[:i]
+	}
+	parts := strings.Split(strings.Trim(release, "."), ".")
+	if len(parts) == 0 || parts[0] == "" {
+		return "==" + version
+	}
+	if len(parts) == 1 {
+		parts = append(parts, "0")
+	}
+	return "~=" + parts[0] + "." + parts[1]
+}
+
+// isUserSpecifier 는 spec 이 pigo 가 적는 ==version 이 아니라 사용자가 적은 범위 조건인지 확인합니다.
+func isUserSpecifier(spec string) bool {
+	spec = strings.TrimSpace(spec)
+	return spec != "" && (!strings.HasPrefix(spec, "==") || strings.ContainsAny(spec, ",*"))
+}
+
+// recordVCSTargets 는 git 에서 설치한 패키지를 requirements.txt 편집기에 기록합니다.
+func recordVCSTargets(reqs *modfile.Requirements, dists []*dist.Distribution, targets []vcsTarget) {
+	for _, t := range targets {
+		line := t.Line
+		if t.Name == "" {
+			// #egg 가 없는 URL 은 설치된 커밋으로 패키지 이름을 찾습니다.
+			for _, d := range dists {
+				if ref, ok := vcsRefOf(d); ok && ref.Rev == t.Pinned.Rev {
+					t.Name = d.Name
+					break
 				}
 			}
+			if t.Name == "" {
+				log.Printf("warning: could not find the package installed from %s; not recorded in requirements.txt", t.Line)
+				continue
+			}
+			line = t.Name + " @ " + t.Line
 		}
-	},
+		reqs.SetLine(t.Name, line)
+	}
+}
+
+// installRequirementsFile 은 pip 에 넘길 requirements 파일을 만듭니다.
+// replace 된 requirement 는 -e <path> 로, git 의존성은 pigo.lock 의 커밋
+// (없으면 지금 해석한 커밋)으로 바꿉니다. 바꿀 것이 없으면 reqPath 를 그대로 돌려줍니다.
+// 고정한 git ref 들도 함께 돌려줍니다.
+func installRequirementsFile(reqPath string, replaced map[string]string, lock *modfile.LockFile) (string, []vcs.Ref, func(), error) {
+	noop := func() {}
+	data, err := os.ReadFile(reqPath)
+	if err != nil {
+		return "", nil, noop, err
+	}
+	var pins []vcs.Ref
+
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+	changed := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if vcs.IsURL(trimmed) {
+			ref, err := vcs.ParseURL(trimmed)
+			if err != nil {
+				return "", nil, noop, err
+			}
+			pinned, err := pinRequirementRef(ref, ref.Egg(), lock)
+			if err != nil {
+				return "", nil, noop, err
+			}
+			lines[i] = pipRef(pinned).String()
+			pins = append(pins, pinned)
+			changed = true
+			continue
+		}
+		req, ok := modfile.ParseRequirementLine(line)
+		if !ok {
+			if rest, ok := strings.CutPrefix(trimmed, "-r "); ok {
+				// 임시 파일에서도 -r 의 상대경로가 깨지지 않게 합니다.
+				if rest = strings.TrimSpace(rest); !filepath.IsAbs(rest) {
+					lines[i] = "-r " + filepath.Join(filepath.Dir(reqPath), rest)
+				}
+			}
+			continue
+		}
+		if dir, ok := replaced[dist.NormalizeName(req.Name)]; ok {
+			lines[i] = "-e " + dir + extrasSuffix(req.Extras)
+			if req.Marker != "" {
+				lines[i] += " ; " + req.Marker
+			}
+			changed = true
+		} else if vcs.IsURL(req.URL) {
+			ref, err := vcs.ParseURL(req.URL)
+			if err != nil {
+				return "", nil, noop, err
+			}
+			pinned, err := pinRequirementRef(ref, req.Name, lock)
+			if err != nil {
+				return "", nil, noop, err
+			}
+			req.URL = pipRef(pinned).RequirementURL()
+			lines[i] = req.String()
+			pins = append(pins, pinned)
+			changed = true
+		}
+	}
+	if !changed {
+		return reqPath, nil, noop, nil
+	}
+
+	tmp, err := os.CreateTemp("", "pigo-requirements-*.txt")
+	if err != nil {
+		return "", nil, noop, err
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+	if _, err := tmp.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", nil, noop, err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", nil, noop, err
+	}
+	return tmp.Name(), pins, cleanup, nil
+}
+
+// pinRequirementRef 는 ref 를 pigo.lock 의 커밋으로, lock 에 없으면 지금 해석한 커밋으로 고정합니다.
+func pinRequirementRef(ref vcs.Ref, name string, lock *modfile.LockFile) (vcs.Ref, error) {
+	if commit, ok := lockedCommit(lock, name, ref); ok {
+		ref.Rev = commit
+		return ref, nil
+	}
+	return pinRef(ref)
 }
 
 func init() {
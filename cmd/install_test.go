@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/janghanul090801/pigo/cmd/modfile"
+)
+
+// testProject 는 t.TempDir() 를 프로젝트 루트와 pigo 캐시로 씁니다.
+func testProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	old := projectRootDir
+	projectRootDir = root
+	t.Cleanup(func() { projectRootDir = old })
+	t.Setenv("PIGO_CACHE", filepath.Join(root, ".cache"))
+	return root
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckOfflineInstallRequirementFiles(t *testing.T) {
+	root := testProject(t)
+	writeTestFile(t, filepath.Join(root, "vendor", "six-1.16.0-py2.py3-none-any.whl"), "")
+	writeTestFile(t, filepath.Join(root, "vendor", "idna-3.6.tar.gz"), "")
+	reqs := filepath.Join(root, "reqs.txt")
+	lock := &modfile.LockFile{}
+
+	writeTestFile(t, reqs, "six==1.16.0\nidna>=3\n")
+	for _, args := range [][]string{
+		{"-r", reqs},
+		{"-r" + reqs},
+		{"--requirement=" + reqs},
+		{"--find-links", filepath.Join(root, "vendor"), "-r", reqs, "six"},
+	} {
+		if err := checkOfflineInstall(args, nil, lock, nil); err != nil {
+			t.Errorf("checkOfflineInstall(%q): %v", args, err)
+		}
+	}
+
+	writeTestFile(t, reqs, "six==1.16.0\nrequests\n")
+	err := checkOfflineInstall([]string{"-c", "constraints.txt", "-r", reqs}, nil, lock, nil)
+	if err == nil {
+		t.Fatal("checkOfflineInstall succeeded with requests missing")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "requests") || strings.Contains(msg, "reqs.txt") || strings.Contains(msg, "constraints.txt") {
+		t.Errorf("checkOfflineInstall error = %q, want only requests missing", msg)
+	}
+
+	if err := checkOfflineInstall([]string{"-r", filepath.Join(root, "missing.txt")}, nil, lock, nil); err == nil {
+		t.Error("checkOfflineInstall succeeded with a missing -r file")
+	}
+}
+
+func TestInstallLockOnly(t *testing.T) {
+	root := testProject(t)
+	var out bytes.Buffer
+	if err := runInstall(nil, installOptions{Mod: "mod", Out: &out}); err == nil || !strings.Contains(err.Error(), "nothing to install") {
+		t.Fatalf("no requirements.txt or pigo.lock: err = %v", err)
+	}
+
+	// requirements.txt 없이 pigo.lock 만 있으면 lock 의 패키지를 설치합니다.
+	writeTestFile(t, filepath.Join(root, modfile.LockFileName), "six 1.16.0\n")
+	writeTestFile(t, filepath.Join(root, ".venv", "lib", "python3.12", "site-packages", "six-1.16.0.dist-info", "METADATA"),
+		"Metadata-Version: 2.1\nName: six\nVersion: 1.16.0\n")
+	if err := runInstall(nil, installOptions{Mod: "mod", Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Already in sync.") {
+		t.Errorf("output = %q, want the lock to be synced", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(root, modfile.RequirementsFileName)); err == nil {
+		t.Error("installing from pigo.lock created requirements.txt")
+	}
+
+	if err := runInstall([]string{"--upgrade"}, installOptions{Mod: "mod", Out: &out}); err == nil {
+		t.Error("pip options without packages or requirements.txt succeeded")
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/janghanul090801/pigo/cmd/vcs"
+)
+
+// lockPath 는 프로젝트의 pigo.lock 경로입니다.
+func lockPath() string {
+	return projectPath(modfile.LockFileName)
+}
+
+// readLock 은 프로젝트의 pigo.lock 을 읽습니다. 없으면 빈 LockFile 입니다.
+func readLock() (*modfile.LockFile, error) {
+	return modfile.ReadLock(lockPath())
+}
+
+// writeLock 은 requirements.txt 에서 전이적으로 필요한, venv 에 설치된 패키지를
+// pigo.lock 에 기록합니다. git 에서 설치한 패키지는 커밋과 pseudo-version 을 적습니다.
+func writeLock() error {
+	var reqs []dist.Requirement
+	if _, err := os.Stat(projectPath("requirements.txt")); err == nil {
+		if reqs, err = readRequirements(projectPath("requirements.txt")); err != nil {
+			return err
+		}
+	}
+	lock, err := buildLock(reqs)
+	if err != nil {
+		return err
+	}
+	return lock.Write(lockPath())
+}
+
+// buildLock 은 reqs 에서 전이적으로 필요한, venv 에 설치된 패키지로 lock 을 만듭니다.
+func buildLock(reqs []dist.Requirement) (*modfile.LockFile, error) {
+	venvPath := venvDir()
+	dists, err := dist.LoadVenv(venvPath)
+	if err != nil {
+		return nil, err
+	}
+	env, err := dist.VenvEnvironment(venvPath)
+	if err != nil {
+		return nil, err
+	}
+	old, err := readLock()
+	if err != nil {
+		return nil, err
+	}
+
+	installed := dist.Index(dists)
+	lock := &modfile.LockFile{}
+	for name := range dist.Closure(installed, reqs, env) {
+		d, ok := installed[name]
+		if !ok {
+			continue
+		}
+		lock.Packages = append(lock.Packages, lockedPackage(d, old))
+	}
+	return lock, nil
+}
+
+// lockedPackage 는 설치된 배포판 하나의 lock 항목을 만듭니다.
+func lockedPackage(d *dist.Distribution, old *modfile.LockFile) modfile.LockedPackage {
+	p := modfile.LockedPackage{Name: d.Name, Version: d.Version}
+	if mod, ok := vendoredModuleOf(d); ok {
+		// vendor/ 의 wheel 로 설치했으면 vendor 할 때의 lock 항목을 그대로 씁니다.
+		return mod.LockedPackage
+	}
+	ref, ok := vcsRefOf(d)
+	if !ok {
+		return p
+	}
+	p.Source = ref.String()
+	if prev, ok := old.Find(d.Name); ok && prev.Source == p.Source {
+		// 같은 커밋이면 git 을 다시 부르지 않습니다.
+		p.Version = prev.Version
+		return p
+	}
+	resolve := vcs.Resolve
+	if offlineMode() {
+		resolve = vcs.ResolveCached
+	}
+	commit, err := resolve(pigoCacheDir(), ref)
+	if err != nil {
+		log.Printf("warning: %s: %v", d.Name, err)
+		return p
+	}
+	p.Version = vcs.PseudoVersion(commit)
+	return p
+}
+
+// vcsRefOf 는 git 에서 설치된 배포판의 저장소와 커밋을 direct_url.json 에서 읽습니다.
+func vcsRefOf(d *dist.Distribution) (vcs.Ref, bool) {
+	du := d.DirectURL
+	if du == nil || du.VCSInfo == nil || du.VCSInfo.VCS != "git" || d.Editable != "" {
+		return vcs.Ref{}, false
+	}
+	repo := du.URL
+	if origin, ok := vcs.Origin(pigoCacheDir(), repo); ok {
+		// offline 에서 캐시의 mirror 로 설치한 경우 원래 저장소로 기록합니다.
+		repo = origin
+	}
+	ref, err := vcs.ParseURL("git+" + repo)
+	if err != nil {
+		return vcs.Ref{}, false
+	}
+	ref.Rev = du.VCSInfo.CommitID
+	if du.Subdirectory != "" {
+		ref.Fragment = "subdirectory=" + du.Subdirectory
+	}
+	return ref, true
+}
+
+// vendoredModuleOf 는 d 가 vendor/ 의 wheel 에서 설치됐다면 modules.txt 항목을 돌려줍니다.
+func vendoredModuleOf(d *dist.Distribution) (modfile.VendoredModule, bool) {
+	path := d.DirectURL.LocalPath()
+	if path == "" || filepath.Dir(path) != vendorDir() {
+		return modfile.VendoredModule{}, false
+	}
+	manifest, err := modfile.ReadVendorManifest(vendorManifestPath())
+	if err != nil {
+		return modfile.VendoredModule{}, false
+	}
+	mod, ok := manifest.Find(d.Name)
+	if !ok || mod.File != filepath.Base(path) {
+		return modfile.VendoredModule{}, false
+	}
+	return mod, true
+}
+
+// installedVCSRef 는 설치된 git 패키지의 저장소와 커밋입니다.
+// vendor/ 의 wheel 로 설치했다면 modules.txt 에 기록된 원본을 씁니다.
+func installedVCSRef(d *dist.Distribution) (vcs.Ref, bool) {
+	if ref, ok := vcsRefOf(d); ok {
+		return ref, true
+	}
+	if mod, ok := vendoredModuleOf(d); ok {
+		if ref, err := vcs.ParseURL(mod.Source); err == nil {
+			return ref, true
+		}
+	}
+	return vcs.Ref{}, false
+}
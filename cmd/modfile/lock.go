@@ -0,0 +1,101 @@
+package modfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+)
+
+// LockFileName 은 설치된 버전을 고정하는 lock 파일 이름입니다.
+const LockFileName = "pigo.lock"
+
+const lockHeader = "# generated by pigo. DO NOT EDIT."
+
+// LockFile 은 pigo.lock 입니다. go.sum 처럼 pigo 가 직접 쓰며, 한 줄에 패키지 하나씩
+// 이름, 버전, (있다면) 설치 원본을 적습니다. VCS 패키지는 pseudo-version 과 커밋을 적습니다.
+//
+//	requests 2.31.0
+//	mylib 0.0.0-20250101120000-abcdefabcdef git+https://github.com/user/mylib.git@abcdef...
+type LockFile struct {
+	Packages []LockedPackage
+}
+
+// LockedPackage 는 lock 파일의 한 줄입니다.
+type LockedPackage struct {
+	Name    string
+	Version string
+	Source  string // VCS 등 인덱스가 아닌 곳에서 받은 경우의 URL
+}
+
+// ReadLock 은 path 의 lock 파일을 읽습니다. 파일이 없으면 빈 LockFile 을 돌려줍니다.
+func ReadLock(path string) (*LockFile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &LockFile{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	l, err := ParseLock(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// ParseLock 은 lock 파일 내용을 읽습니다.
+func ParseLock(content string) (*LockFile, error) {
+	l := &LockFile{}
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected <name> <version> [source]", i+1)
+		}
+		p := LockedPackage{Name: fields[0], Version: fields[1]}
+		if len(fields) == 3 {
+			p.Source = fields[2]
+		}
+		l.Packages = append(l.Packages, p)
+	}
+	return l, nil
+}
+
+// Find 는 name 의 항목을 찾습니다. 이름은 정규화해서 비교합니다.
+func (l *LockFile) Find(name string) (LockedPackage, bool) {
+	for _, p := range l.Packages {
+		if dist.NormalizeName(p.Name) == dist.NormalizeName(name) {
+			return p, true
+		}
+	}
+	return LockedPackage{}, false
+}
+
+// Format 은 이름순으로 정렬한 lock 파일 내용을 돌려줍니다.
+func (l *LockFile) Format() []byte {
+	packages := append([]LockedPackage(nil), l.Packages...)
+	sort.Slice(packages, func(i, j int) bool {
+		return dist.NormalizeName(packages[i].Name) < dist.NormalizeName(packages[j].Name)
+	})
+	var buf bytes.Buffer
+	buf.WriteString(lockHeader + "\n")
+	for _, p := range packages {
+		buf.WriteString(p.Name + " " + p.Version)
+		if p.Source != "" {
+			buf.WriteString(" " + p.Source)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// Write 는 lock 파일을 path 에 씁니다.
+func (l *LockFile) Write(path string) error {
+	return os.WriteFile(path, l.Format(), 0644)
+}
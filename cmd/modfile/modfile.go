@@ -0,0 +1,256 @@
+package modfile
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+)
+
+// FileName 은 프로젝트 manifest 파일 이름입니다.
+const FileName = "pigo.mod"
+
+// File 은 pigo.mod 입니다. go.mod 처럼 한 줄에 하나의 지시어를 씁니다.
+//
+//	python 3.12
+//	replace somepkg => ../somepkg
+//	script test = pytest -q
+//	env DEBUG=1
+//	env test PYTHONWARNINGS=error
+//
+// 주석(#, //)과 빈 줄은 그대로 보존되며, Set* 함수들은 해당 줄만 고칩니다.
+type File struct {
+	Python  string
+	Replace []Replace
+	Scripts []Script
+	Env     []EnvVar
+
+	lines []string
+}
+
+// Replace 는 패키지를 로컬 경로에서 editable 로 설치하도록 바꾸는 지시어입니다.
+type Replace struct {
+	Name string
+	Path string // pigo.mod 기준 상대경로 또는 절대경로
+}
+
+// Script 는 pigo run <name> 으로 실행하는 명령입니다.
+type Script struct {
+	Name    string
+	Command string // 셸을 거치지 않고 따옴표 규칙으로만 나눠서 실행합니다.
+}
+
+// EnvVar 는 pigo run 이 실행하는 프로세스에 넣을 환경 변수입니다.
+// Script 가 있으면 그 script 를 실행할 때만 씁니다.
+type EnvVar struct {
+	Script string
+	Name   string
+	Value  string
+}
+
+// Read 는 path 의 manifest 를 읽습니다. 파일이 없으면 빈 File 을 돌려줍니다.
+func Read(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &File{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	f, err := Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Parse 는 manifest 내용을 읽습니다.
+func Parse(content string) (*File, error) {
+	f := &File{}
+	content = strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if content != "" {
+		f.lines = strings.Split(content, "\n")
+	}
+	directives, err := parseDirectives(f.lines)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range directives {
+		switch d.verb {
+		case "python":
+			if len(d.args) != 1 {
+				return nil, fmt.Errorf("line %d: usage: python <version>", d.line+1)
+			}
+			f.Python = d.args[0]
+		case "replace":
+			if len(d.args) != 3 || d.args[1] != "=>" {
+				return nil, fmt.Errorf("line %d: usage: replace <package> => <path>", d.line+1)
+			}
+			f.Replace = append(f.Replace, Replace{Name: d.args[0], Path: d.args[2]})
+		case "script":
+			name, command, ok := strings.Cut(d.text, "=")
+			name, command = strings.TrimSpace(name), unquote(strings.TrimSpace(command))
+			if !ok || name == "" || strings.ContainsAny(name, " \t") || command == "" {
+				return nil, fmt.Errorf("line %d: usage: script <name> = <command>", d.line+1)
+			}
+			f.Scripts = append(f.Scripts, Script{Name: name, Command: command})
+		case "env":
+			// = 앞은 NAME 또는 script NAME 입니다. (env DEBUG = 1, env test DEBUG=1)
+			left, value, ok := strings.Cut(d.text, "=")
+			fields := strings.Fields(left)
+			if !ok || len(fields) == 0 || len(fields) > 2 || !envNameRe.MatchString(fields[len(fields)-1]) {
+				return nil, fmt.Errorf("line %d: usage: env [script] <NAME>=<value>", d.line+1)
+			}
+			script := ""
+			if len(fields) == 2 {
+				script = fields[0]
+			}
+			f.Env = append(f.Env, EnvVar{Script: script, Name: fields[len(fields)-1], Value: unquote(strings.TrimSpace(value))})
+		default:
+			// pigo.mod 는 TOML 이 아니므로 [scripts] 표 대신 script 지시어를 씁니다.
+			if strings.HasPrefix(d.verb, "[") {
+				return nil, fmt.Errorf("line %d: %s: %s has no [sections]; declare scripts as: script <name> = <command>", d.line+1, d.verb, FileName)
+			}
+			return nil, fmt.Errorf("line %d: unknown directive %q", d.line+1, d.verb)
+		}
+	}
+	return f, nil
+}
+
+// Write 는 manifest 를 path 에 씁니다.
+func (f *File) Write(path string) error {
+	return os.WriteFile(path, f.Format(), 0644)
+}
+
+// Format 은 manifest 내용을 돌려줍니다.
+func (f *File) Format() []byte {
+	if len(f.lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(f.lines, "\n") + "\n")
+}
+
+// SetPython 은 python 지시어를 바꾸거나 맨 위에 추가합니다.
+func (f *File) SetPython(version string) {
+	f.Python = version
+	line := "python " + version
+	directives, _ := parseDirectives(f.lines)
+	for _, d := range directives {
+		if d.verb == "python" && !d.block {
+			f.lines[d.line] = line
+			return
+		}
+	}
+	f.lines = append([]string{line}, f.lines...)
+}
+
+// FindReplace 는 name 에 대한 replace 지시어를 찾습니다. 이름은 정규화해서 비교합니다.
+func (f *File) FindReplace(name string) (Replace, bool) {
+	for _, r := range f.Replace {
+		if dist.NormalizeName(r.Name) == dist.NormalizeName(name) {
+			return r, true
+		}
+	}
+	return Replace{}, false
+}
+
+// FindScript 는 이름이 name 인 script 지시어를 찾습니다.
+func (f *File) FindScript(name string) (Script, bool) {
+	for _, s := range f.Scripts {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Script{}, false
+}
+
+// ScriptEnv 는 script 를 실행할 때 넣을 환경 변수입니다. 모든 실행에 쓰는 env 뒤에
+// 해당 script 의 env 가 오므로 같은 이름이면 뒤의 값이 이깁니다. script 가 "" 이면 공통 env 만 돌려줍니다.
+func (f *File) ScriptEnv(script string) []EnvVar {
+	var vars []EnvVar
+	for _, v := range f.Env {
+		if v.Script == "" {
+			vars = append(vars, v)
+		}
+	}
+	for _, v := range f.Env {
+		if script != "" && v.Script == script {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
+
+// envNameRe 는 환경 변수 이름입니다.
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// unquote 는 값 전체를 감싼 따옴표를 벗깁니다.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// directive 는 지시어 한 줄입니다. go.mod 처럼 "verb (" ... ")" 블록 안의 줄은
+// 블록의 verb 를 물려받습니다.
+type directive struct {
+	verb  string
+	args  []string
+	text  string // verb 뒤의 주석을 뺀 원문
+	line  int    // 0 부터 시작하는 줄 번호
+	block bool
+}
+
+func parseDirectives(lines []string) ([]directive, error) {
+	var result []directive
+	blockVerb, blockStart := "", 0
+	for i, raw := range lines {
+		text := stripComment(raw)
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if blockVerb != "" {
+			if len(fields) == 1 && fields[0] == ")" {
+				blockVerb = ""
+				continue
+			}
+			result = append(result, directive{verb: blockVerb, args: fields, text: text, line: i, block: true})
+			continue
+		}
+		if len(fields) == 2 && fields[1] == "(" {
+			blockVerb, blockStart = fields[0], i
+			continue
+		}
+		text = strings.TrimSpace(strings.TrimPrefix(text, fields[0]))
+		result = append(result, directive{verb: fields[0], args: fields[1:], text: text, line: i})
+	}
+	if blockVerb != "" {
+		return nil, fmt.Errorf("line %d: unterminated %s block", blockStart+1, blockVerb)
+	}
+	return result, nil
+}
+
+// blockEnd 는 "verb (" 블록을 닫는 ")" 의 줄 번호를 찾습니다. 없으면 -1 입니다.
+func blockEnd(lines []string, verb string) int {
+	inBlock := false
+	for i, raw := range lines {
+		fields := strings.Fields(stripComment(raw))
+		switch {
+		case len(fields) == 2 && fields[0] == verb && fields[1] == "(":
+			inBlock = true
+		case inBlock && len(fields) == 1 && fields[0] == ")":
+			return i
+		}
+	}
+	return -1
+}
+
+// 주석은 줄 처음이나 공백 뒤의 # 또는 // 부터입니다. (URL 안의 // 는 주석이 아닙니다.)
+var commentRe = regexp.MustCompile(`(^|\s)(#|//).*$`)
+
+func stripComment(line string) string {
+	return strings.TrimSpace(commentRe.ReplaceAllString(line, ""))
+}
@@ -0,0 +1,76 @@
+package modfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnv(t *testing.T) {
+	tests := []struct {
+		line string
+		want EnvVar
+	}{
+		{`env DEBUG=1`, EnvVar{Name: "DEBUG", Value: "1"}},
+		{`env DEBUG = 1`, EnvVar{Name: "DEBUG", Value: "1"}},
+		{`env DEBUG=`, EnvVar{Name: "DEBUG"}},
+		{`env URL="http://x/?a=b"`, EnvVar{Name: "URL", Value: "http://x/?a=b"}},
+		{`env test PYTHONWARNINGS=error`, EnvVar{Script: "test", Name: "PYTHONWARNINGS", Value: "error"}},
+		{`env test PYTHONWARNINGS = error  # 주석`, EnvVar{Script: "test", Name: "PYTHONWARNINGS", Value: "error"}},
+	}
+	for _, tt := range tests {
+		f, err := Parse(tt.line)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.line, err)
+			continue
+		}
+		if len(f.Env) != 1 || f.Env[0] != tt.want {
+			t.Errorf("Parse(%q).Env = %+v, want %+v", tt.line, f.Env, tt.want)
+		}
+	}
+
+	for _, line := range []string{
+		`env DEBUG`,
+		`env =1`,
+		`env test`,
+		`env a b C=1`,
+		`env 1DEBUG=1`,
+		`env test DE-BUG=1`,
+	} {
+		if _, err := Parse(line); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", line)
+		}
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	f, err := Parse("script test = pytest -q\nscript (\n\tserve = \"uvicorn app:app --reload\"\n\tlint=ruff check .\n)\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Script{
+		{Name: "test", Command: "pytest -q"},
+		{Name: "serve", Command: "uvicorn app:app --reload"},
+		{Name: "lint", Command: "ruff check ."},
+	}
+	if !reflect.DeepEqual(f.Scripts, want) {
+		t.Errorf("Scripts = %+v, want %+v", f.Scripts, want)
+	}
+
+	for _, content := range []string{
+		"script test",
+		"script = pytest",
+		"script my test = pytest",
+		"script test =",
+	} {
+		if _, err := Parse(content); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", content)
+		}
+	}
+
+	// [scripts] 표는 script 지시어로 쓰라고 알려줍니다.
+	_, err = Parse("[scripts]\ntest = \"pytest -q\"\n")
+	if err == nil || !strings.Contains(err.Error(), "script <name> = <command>") {
+		t.Errorf("Parse([scripts]) = %v, want a hint to use the script directive", err)
+	}
+}
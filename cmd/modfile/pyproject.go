@@ -0,0 +1,143 @@
+package modfile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PyprojectFileName 은 PEP 517/621 프로젝트 설정 파일 이름입니다.
+const PyprojectFileName = "pyproject.toml"
+
+// Pyproject 는 pyproject.toml 에서 pigo build 가 쓰는 부분입니다.
+type Pyproject struct {
+	Project     *Project       `toml:"project"`
+	BuildSystem *BuildSystem   `toml:"build-system"`
+	Tool        map[string]any `toml:"tool"`
+}
+
+// BuildSystem 은 PEP 517/518 [build-system] 테이블입니다.
+type BuildSystem struct {
+	Requires     []string `toml:"requires"`
+	BuildBackend string   `toml:"build-backend"`
+	BackendPath  []string `toml:"backend-path"`
+}
+
+// Project 는 PEP 621 [project] 테이블입니다.
+type Project struct {
+	Name                 string                       `toml:"name"`
+	Version              string                       `toml:"version"`
+	Description          string                       `toml:"description"`
+	Readme               *Readme                      `toml:"readme"`
+	RequiresPython       string                       `toml:"requires-python"`
+	License              *License                     `toml:"license"`
+	LicenseFiles         []string                     `toml:"license-files"`
+	Authors              []Person                     `toml:"authors"`
+	Maintainers          []Person                     `toml:"maintainers"`
+	Keywords             []string                     `toml:"keywords"`
+	Classifiers          []string                     `toml:"classifiers"`
+	URLs                 map[string]string            `toml:"urls"`
+	Scripts              map[string]string            `toml:"scripts"`
+	GUIScripts           map[string]string            `toml:"gui-scripts"`
+	EntryPoints          map[string]map[string]string `toml:"entry-points"`
+	Dependencies         []string                     `toml:"dependencies"`
+	OptionalDependencies map[string][]string          `toml:"optional-dependencies"`
+	Dynamic              []string                     `toml:"dynamic"`
+}
+
+// Person 은 authors, maintainers 의 한 항목입니다.
+type Person struct {
+	Name  string `toml:"name"`
+	Email string `toml:"email"`
+}
+
+// Readme 는 readme = "README.md" 또는 readme = {file = ..., content-type = ...} 입니다.
+type Readme struct {
+	File        string
+	Text        string
+	ContentType string
+}
+
+// UnmarshalTOML 은 문자열과 테이블 형식을 모두 읽습니다.
+func (r *Readme) UnmarshalTOML(v any) error {
+	switch v := v.(type) {
+	case string:
+		r.File = v
+		return nil
+	case map[string]any:
+		r.File, _ = v["file"].(string)
+		r.Text, _ = v["text"].(string)
+		r.ContentType, _ = v["content-type"].(string)
+		if r.File == "" && r.Text == "" {
+			return fmt.Errorf("readme: file or text is required")
+		}
+		return nil
+	}
+	return fmt.Errorf("readme: expected a string or a table")
+}
+
+// License 는 license = "MIT" (PEP 639 SPDX 표현식) 또는 license = {file = ...} / {text = ...} 입니다.
+type License struct {
+	Expression string
+	File       string
+	Text       string
+}
+
+// UnmarshalTOML 은 문자열과 테이블 형식을 모두 읽습니다.
+func (l *License) UnmarshalTOML(v any) error {
+	switch v := v.(type) {
+	case string:
+		l.Expression = v
+		return nil
+	case map[string]any:
+		l.File, _ = v["file"].(string)
+		l.Text, _ = v["text"].(string)
+		if l.File == "" && l.Text == "" {
+			return fmt.Errorf("license: file or text is required")
+		}
+		return nil
+	}
+	return fmt.Errorf("license: expected a string or a table")
+}
+
+// ReadPyproject 는 path 의 pyproject.toml 을 읽습니다. 파일이 없으면 빈 Pyproject 를 돌려줍니다.
+func ReadPyproject(path string) (*Pyproject, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Pyproject{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var p Pyproject
+	if _, err := toml.Decode(string(data), &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// HasTool 은 [tool.hatch.build] 같은 테이블이 있는지 확인합니다. path 는 "hatch.build" 처럼 씁니다.
+func (p *Pyproject) HasTool(path string) bool {
+	var cur any = p.Tool
+	for _, key := range strings.Split(path, ".") {
+		table, ok := cur.(map[string]any)
+		if !ok {
+			return false
+		}
+		if cur, ok = table[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDynamic 은 field 를 build backend 가 채우도록 dynamic 에 적었는지 확인합니다.
+func (p *Project) IsDynamic(field string) bool {
+	for _, f := range p.Dynamic {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package modfile
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/vcs"
+)
+
+// RequirementsFileName 은 프로젝트가 선언한 의존성 파일 이름입니다.
+const RequirementsFileName = "requirements.txt"
+
+// requirementSuffixRe 는 요구사항 뒤에 붙는 줄 이어쓰기(\), 옵션(--hash=...), 주석의 시작입니다.
+var requirementSuffixRe = regexp.MustCompile(`\s*\\(?:\n|$)|\s+(?:--?[A-Za-z]|#)`)
+
+// splitRequirementLine 은 requirements.txt 의 한 항목을 요구사항과 그 뒤의 옵션, 주석으로 나눕니다.
+// suffix 는 앞의 공백과 이어지는 줄까지 적힌 그대로입니다.
+func splitRequirementLine(line string) (spec, suffix string) {
+	if strings.HasPrefix(strings.TrimSpace(line), "-") {
+		return line, ""
+	}
+	if loc := requirementSuffixRe.FindStringIndex(line); loc != nil {
+		return line[:loc[0]], line[loc[0]:]
+	}
+	return line, ""
+}
+
+// ParseRequirementLine 은 requirements.txt 의 한 줄을 읽습니다. \ 로 이어진 줄은 하나로 합쳐서 넘깁니다.
+// 주석, 빈 줄, pip 옵션(-r, -e, --index-url ...)은 false 를 돌려주고,
+// 요구사항 뒤의 옵션(--hash=...)은 무시합니다.
+// git+https://...#egg=name 처럼 URL 만 있는 줄은 #egg 가 있을 때만 읽습니다.
+func ParseRequirementLine(line string) (dist.Requirement, bool) {
+	spec, _ := splitRequirementLine(line)
+	line = strings.TrimSpace(spec)
+	if line == "" || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "#") {
+		return dist.Requirement{}, false
+	}
+	if vcs.IsURL(line) {
+		ref, err := vcs.ParseURL(line)
+		if err != nil || ref.Egg() == "" {
+			return dist.Requirement{}, false
+		}
+		return dist.Requirement{Name: ref.Egg(), URL: line}, true
+	}
+	req, err := dist.ParseRequirement(line)
+	if err != nil {
+		return dist.Requirement{}, false
+	}
+	return req, true
+}
+
+// Requirements 는 requirements.txt 편집기입니다.
+// 이름은 PEP 503 으로 정규화해서 비교하고, Format 은 pip 옵션, 인덱스 패키지,
+// URL(git 등) 패키지 순서로 묶어 각각 이름순으로 정렬합니다.
+// 항목 위의 주석(빈 줄로 떨어져 있어도)과 항목 뒤의 옵션(--hash=...), 주석은 적힌 그대로 항목과 함께 움직입니다.
+type Requirements struct {
+	header  []string // 맨 위의 주석과 pip 옵션 (순서 유지)
+	entries []requirementEntry
+	footer  []string // 항목에 붙지 않은 나머지 주석
+}
+
+type requirementEntry struct {
+	comments []string // 위의 주석 줄
+	line     string   // \ 로 이어진 줄은 \n 으로 합친 그대로
+	req      dist.Requirement
+}
+
+// ReadRequirements 는 path 의 requirements.txt 를 읽습니다. 파일이 없으면 빈 편집기입니다.
+func ReadRequirements(path string) (*Requirements, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Requirements{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return ParseRequirements(string(data)), nil
+}
+
+// ParseRequirements 는 requirements.txt 내용을 읽습니다.
+func ParseRequirements(content string) *Requirements {
+	r := &Requirements{}
+	var pending []string
+	content = strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if content == "" {
+		return r
+	}
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			// 첫 항목 전에 빈 줄로 떨어진 주석은 파일 머리말입니다.
+			// 그 뒤의 주석은 빈 줄이 있어도 다음 항목에 붙습니다.
+			if len(r.entries) == 0 {
+				r.header = append(r.header, pending...)
+				pending = nil
+			}
+		case strings.HasPrefix(trimmed, "#"):
+			pending = append(pending, line)
+		default:
+			// \ 로 끝나는 줄은 다음 줄과 한 항목입니다. (--hash 를 줄마다 쓰는 경우)
+			for strings.HasSuffix(strings.TrimRight(line, " \t"), "\\") && i+1 < len(lines) {
+				i++
+				line += "\n" + lines[i]
+			}
+			req, ok := ParseRequirementLine(line)
+			if !ok {
+				// pip 옵션이나 읽을 수 없는 줄은 순서대로 맨 위에 둡니다.
+				pending = append(pending, line)
+				r.header = append(r.header, pending...)
+				pending = nil
+				continue
+			}
+			r.entries = append(r.entries, requirementEntry{comments: pending, line: line, req: req})
+			pending = nil
+		}
+	}
+	if len(r.entries) == 0 {
+		r.header = append(r.header, pending...)
+	} else {
+		r.footer = append(r.footer, pending...)
+	}
+	return r
+}
+
+// List 는 파일의 requirement 들입니다.
+func (r *Requirements) List() []dist.Requirement {
+	var reqs []dist.Requirement
+	for _, e := range r.entries {
+		reqs = append(reqs, e.req)
+	}
+	return reqs
+}
+
+// Find 는 name 의 requirement 를 찾습니다.
+func (r *Requirements) Find(name string) (dist.Requirement, bool) {
+	if i := r.index(name); i >= 0 {
+		return r.entries[i].req, true
+	}
+	return dist.Requirement{}, false
+}
+
+func (r *Requirements) index(name string) int {
+	for i, e := range r.entries {
+		if dist.NormalizeName(e.req.Name) == dist.NormalizeName(name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Set 은 req 의 줄을 바꾸거나 추가합니다. 기존 줄 뒤의 옵션과 주석은 남깁니다.
+func (r *Requirements) Set(req dist.Requirement) {
+	r.SetLine(req.Name, req.String())
+}
+
+// SetLine 은 name 의 줄을 line 으로 바꾸거나 추가합니다. 요구사항이 그대로면 줄을 건드리지 않고,
+// 바뀌면 기존 줄의 옵션(--hash=...)과 주석을 적힌 그대로 새 요구사항 뒤에 붙입니다.
+func (r *Requirements) SetLine(name, line string) {
+	req, ok := ParseRequirementLine(line)
+	if !ok {
+		req = dist.Requirement{Name: name}
+	}
+	if i := r.index(name); i >= 0 {
+		old, suffix := splitRequirementLine(r.entries[i].line)
+		if strings.TrimSpace(old) == strings.TrimSpace(line) {
+			return
+		}
+		if suffix != "" {
+			if _, lineSuffix := splitRequirementLine(line); lineSuffix == "" {
+				line += suffix
+			}
+		}
+		r.entries[i].line, r.entries[i].req = line, req
+		return
+	}
+	r.entries = append(r.entries, requirementEntry{line: line, req: req})
+}
+
+// Remove 는 name 의 줄을 지웁니다. 있었으면 true 입니다.
+func (r *Requirements) Remove(name string) bool {
+	i := r.index(name)
+	if i < 0 {
+		return false
+	}
+	r.entries = append(r.entries[:i], r.entries[i+1:]...)
+	return true
+}
+
+// Format 은 정렬하고 묶은 requirements.txt 내용을 돌려줍니다.
+func (r *Requirements) Format() []byte {
+	var index, direct []requirementEntry
+	for _, e := range r.entries {
+		if e.req.URL != "" {
+			direct = append(direct, e)
+		} else {
+			index = append(index, e)
+		}
+	}
+
+	var blocks [][]string
+	if len(r.header) > 0 {
+		blocks = append(blocks, r.header)
+	}
+	for _, group := range [][]requirementEntry{index, direct} {
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return dist.NormalizeName(group[i].req.Name) < dist.NormalizeName(group[j].req.Name)
+		})
+		var lines []string
+		for _, e := range group {
+			lines = append(append(lines, e.comments...), e.line)
+		}
+		blocks = append(blocks, lines)
+	}
+	if len(r.footer) > 0 {
+		blocks = append(blocks, r.footer)
+	}
+
+	var b strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, line := range block {
+			b.WriteString(line + "\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// Write 는 requirements.txt 를 path 에 씁니다.
+func (r *Requirements) Write(path string) error {
+	return os.WriteFile(path, r.Format(), 0644)
+}
@@ -0,0 +1,115 @@
+package modfile
+
+import (
+	"testing"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+)
+
+func TestParseRequirementLineOptions(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"requests==2.31.0", "requests==2.31.0"},
+		{"requests==2.31.0 --hash=sha256:abc", "requests==2.31.0"},
+		{"requests == 2.31.0  --hash sha256:abc  # http", "requests==2.31.0"},
+		{"requests==2.31.0 \\", "requests==2.31.0"},
+		{"requests==2.31.0 \\\n    --hash=sha256:abc", "requests==2.31.0"},
+		{`pywin32>=306; sys_platform == "win32" --config-settings=x=y`, `pywin32>=306; sys_platform == "win32"`},
+		{"git+https://example.com/x.git#egg=x --hash=sha256:abc", "x @ git+https://example.com/x.git#egg=x"},
+	}
+	for _, tt := range tests {
+		req, ok := ParseRequirementLine(tt.line)
+		if !ok {
+			t.Errorf("ParseRequirementLine(%q) failed", tt.line)
+			continue
+		}
+		if got := req.String(); got != tt.want {
+			t.Errorf("ParseRequirementLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+	for _, line := range []string{"--hash=sha256:abc", "    --hash=sha256:abc \\", "-r base.txt", "# requests"} {
+		if _, ok := ParseRequirementLine(line); ok {
+			t.Errorf("ParseRequirementLine(%q) succeeded, want false", line)
+		}
+	}
+}
+
+func TestRequirementsKeepsOptions(t *testing.T) {
+	content := "--require-hashes\n" +
+		"\n" +
+		"six==1.16.0 --hash=sha256:1e61c374  # compat\n" +
+		"requests==2.31.0 \\\n" +
+		"    --hash=sha256:58cd2187 \\\n" +
+		"    --hash=sha256:942c5a75\n"
+	r := ParseRequirements(content)
+	if got := len(r.List()); got != 2 {
+		t.Fatalf("List() has %d entries, want 2", got)
+	}
+
+	// 바뀌지 않은 항목은 적힌 그대로 다시 씁니다.
+	r.Set(dist.Requirement{Name: "requests", Specifier: "==2.31.0"})
+	r.Set(dist.Requirement{Name: "six", Specifier: "==1.16.0"})
+	want := "--require-hashes\n" +
+		"\n" +
+		"requests==2.31.0 \\\n" +
+		"    --hash=sha256:58cd2187 \\\n" +
+		"    --hash=sha256:942c5a75\n" +
+		"six==1.16.0 --hash=sha256:1e61c374  # compat\n"
+	if got := string(r.Format()); got != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+
+	// 버전을 바꾸면 옵션과 주석은 적힌 그대로 따라옵니다.
+	r.Set(dist.Requirement{Name: "six", Specifier: "==1.17.0"})
+	if e := r.entries[r.index("six")]; e.line != "six==1.17.0 --hash=sha256:1e61c374  # compat" {
+		t.Errorf("six line = %q", e.line)
+	}
+}
+
+func TestRequirementsComments(t *testing.T) {
+	content := "# generated by hand\n" +
+		"\n" +
+		"# web\n" +
+		"requests==2.31.0\n" +
+		"\n" +
+		"# testing tools\n" +
+		"# (dev only)\n" +
+		"\n" +
+		"pytest==8.0.0\n" +
+		"\n" +
+		"# data\n" +
+		"attrs==23.1.0\n" +
+		"\n" +
+		"# trailing note\n"
+	r := ParseRequirements(content)
+	want := "# generated by hand\n" +
+		"\n" +
+		"# data\n" +
+		"attrs==23.1.0\n" +
+		"# testing tools\n" +
+		"# (dev only)\n" +
+		"pytest==8.0.0\n" +
+		"# web\n" +
+		"requests==2.31.0\n" +
+		"\n" +
+		"# trailing note\n"
+	if got := string(r.Format()); got != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+
+	// 주석은 지운 항목과 함께 사라지고 다른 항목에 붙지 않습니다.
+	r.Remove("pytest")
+	want = "# generated by hand\n" +
+		"\n" +
+		"# data\n" +
+		"attrs==23.1.0\n" +
+		"# web\n" +
+		"requests==2.31.0\n" +
+		"\n" +
+		"# trailing note\n"
+	if got := string(r.Format()); got != want {
+		t.Errorf("Format() after Remove =\n%s\nwant\n%s", got, want)
+	}
+}
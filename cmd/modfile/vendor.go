@@ -0,0 +1,110 @@
+package modfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+)
+
+// VendorDir 는 pigo vendor 가 패키지를 모아 두는 디렉토리입니다.
+const VendorDir = "vendor"
+
+// VendorManifestName 은 vendor 디렉토리 안의 목록 파일 이름입니다.
+const VendorManifestName = "modules.txt"
+
+const vendorHeader = "# generated by pigo vendor. DO NOT EDIT."
+
+// VendorManifest 는 vendor/modules.txt 입니다. 패키지마다 lock 항목과
+// vendor 안의 파일 이름, sha256 을 한 줄에 적습니다.
+//
+//	requests 2.31.0 requests-2.31.0-py3-none-any.whl sha256=58cd...
+//	mylib 0.0.0-20250101120000-abcdefabcdef mylib-1.0-py3-none-any.whl sha256=9f86... git+https://...
+type VendorManifest struct {
+	Modules []VendoredModule
+}
+
+// VendoredModule 은 modules.txt 의 한 줄입니다.
+type VendoredModule struct {
+	LockedPackage
+	File   string
+	SHA256 string
+}
+
+// ReadVendorManifest 는 path 의 modules.txt 를 읽습니다.
+func ReadVendorManifest(path string) (*VendorManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := ParseVendorManifest(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// ParseVendorManifest 는 modules.txt 내용을 읽습니다.
+func ParseVendorManifest(content string) (*VendorManifest, error) {
+	m := &VendorManifest{}
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 && len(fields) != 5 {
+			return nil, fmt.Errorf("line %d: expected <name> <version> <file> sha256=<hash> [source]", i+1)
+		}
+		sum, ok := strings.CutPrefix(fields[3], "sha256=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected sha256=<hash>, got %q", i+1, fields[3])
+		}
+		mod := VendoredModule{
+			LockedPackage: LockedPackage{Name: fields[0], Version: fields[1]},
+			File:          fields[2],
+			SHA256:        sum,
+		}
+		if len(fields) == 5 {
+			mod.Source = fields[4]
+		}
+		m.Modules = append(m.Modules, mod)
+	}
+	return m, nil
+}
+
+// Find 는 name 의 항목을 찾습니다. 이름은 정규화해서 비교합니다.
+func (m *VendorManifest) Find(name string) (VendoredModule, bool) {
+	for _, mod := range m.Modules {
+		if dist.NormalizeName(mod.Name) == dist.NormalizeName(name) {
+			return mod, true
+		}
+	}
+	return VendoredModule{}, false
+}
+
+// Format 은 이름순으로 정렬한 modules.txt 내용을 돌려줍니다.
+func (m *VendorManifest) Format() []byte {
+	modules := append([]VendoredModule(nil), m.Modules...)
+	sort.Slice(modules, func(i, j int) bool {
+		return dist.NormalizeName(modules[i].Name) < dist.NormalizeName(modules[j].Name)
+	})
+	var buf bytes.Buffer
+	buf.WriteString(vendorHeader + "\n")
+	for _, mod := range modules {
+		fmt.Fprintf(&buf, "%s %s %s sha256=%s", mod.Name, mod.Version, mod.File, mod.SHA256)
+		if mod.Source != "" {
+			buf.WriteString(" " + mod.Source)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// Write 는 modules.txt 를 path 에 씁니다.
+func (m *VendorManifest) Write(path string) error {
+	return os.WriteFile(path, m.Format(), 0644)
+}
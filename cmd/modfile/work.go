@@ -0,0 +1,85 @@
+package modfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WorkFileName 은 workspace 파일 이름입니다.
+const WorkFileName = "pigo.work"
+
+// WorkFile 은 pigo.work 입니다. go.work 처럼 workspace 에 속한 프로젝트를 나열합니다.
+//
+//	use (
+//		./services/api
+//		./services/worker
+//	)
+type WorkFile struct {
+	Use []string
+
+	lines []string
+}
+
+// ReadWork 는 path 의 workspace 파일을 읽습니다.
+func ReadWork(path string) (*WorkFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := ParseWork(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return w, nil
+}
+
+// ParseWork 는 workspace 파일 내용을 읽습니다.
+func ParseWork(content string) (*WorkFile, error) {
+	w := &WorkFile{}
+	content = strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if content != "" {
+		w.lines = strings.Split(content, "\n")
+	}
+	directives, err := parseDirectives(w.lines)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range directives {
+		switch d.verb {
+		case "use":
+			if len(d.args) != 1 {
+				return nil, fmt.Errorf("line %d: usage: use <dir>", d.line+1)
+			}
+			w.Use = append(w.Use, d.args[0])
+		default:
+			return nil, fmt.Errorf("line %d: unknown directive %q", d.line+1, d.verb)
+		}
+	}
+	return w, nil
+}
+
+// AddUse 는 dir 을 use 블록에 추가합니다. 이미 있으면 아무것도 하지 않습니다.
+func (w *WorkFile) AddUse(dir string) {
+	for _, existing := range w.Use {
+		if filepath.Clean(existing) == filepath.Clean(dir) {
+			return
+		}
+	}
+	w.Use = append(w.Use, dir)
+
+	if end := blockEnd(w.lines, "use"); end != -1 {
+		w.lines = append(w.lines[:end], append([]string{"\t" + dir}, w.lines[end:]...)...)
+		return
+	}
+	if len(w.lines) > 0 {
+		w.lines = append(w.lines, "")
+	}
+	w.lines = append(w.lines, "use (", "\t"+dir, ")")
+}
+
+// Write 는 workspace 파일을 path 에 씁니다.
+func (w *WorkFile) Write(path string) error {
+	return os.WriteFile(path, []byte(strings.Join(w.lines, "\n")+"\n"), 0644)
+}
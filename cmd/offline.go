@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/janghanul090801/pigo/cmd/vcs"
+)
+
+// offlineMode 는 --offline 이나 PIGO_OFFLINE=1 일 때 true 입니다.
+// 이때 pigo 는 vendor/, pigo 캐시, 설치된 venv 만 사용하고 네트워크를 쓰지 않습니다.
+func offlineMode() bool {
+	if offline {
+		return true
+	}
+	on, _ := strconv.ParseBool(os.Getenv("PIGO_OFFLINE"))
+	return on
+}
+
+// localFindLinks 는 offline 에서 pip 이 패키지를 찾는 로컬 디렉토리입니다. (vendor/, 캐시의 wheels/)
+func localFindLinks() []string {
+	var dirs []string
+	for _, dir := range []string{vendorDir(), filepath.Join(pigoCacheDir(), "wheels")} {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// offlinePipArgs 는 pip 이 인덱스 대신 로컬 디렉토리만 보게 하는 옵션입니다.
+func offlinePipArgs() []string {
+	args := []string{"--no-index"}
+	for _, dir := range localFindLinks() {
+		args = append(args, "--find-links", dir)
+	}
+	return args
+}
+
+// offlineEnv 는 pip 이 부르는 git 이 로컬 저장소(file://)만 쓰게 하는 환경 변수입니다.
+func offlineEnv() []string {
+	return append(os.Environ(), "GIT_ALLOW_PROTOCOL=file", "PIP_NO_INDEX=1")
+}
+
+// pipRef 는 pip 에 넘길 git ref 입니다. offline 이면 캐시의 mirror 에서 받게 합니다.
+func pipRef(pinned vcs.Ref) vcs.Ref {
+	if !offlineMode() {
+		return pinned
+	}
+	mirror := vcs.MirrorPath(pigoCacheDir(), pinned.Repo)
+	if _, err := os.Stat(filepath.Join(mirror, "HEAD")); err == nil {
+		pinned.Repo = "file://" + filepath.ToSlash(mirror)
+	}
+	return pinned
+}
+
+// localArtifacts 는 정규화된 패키지 이름 -> 로컬에 있는 wheel/sdist 버전들입니다.
+type localArtifacts map[string][]string
+
+// scanLocalArtifacts 는 dirs 의 wheel 과 sdist 파일 이름에서 이름과 버전을 읽습니다.
+func scanLocalArtifacts(dirs []string) localArtifacts {
+	result := make(localArtifacts)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, version, ok := parseArtifactName(e.Name())
+			if ok {
+				key := dist.NormalizeName(name)
+				result[key] = append(result[key], version)
+			}
+		}
+	}
+	return result
+}
+
+// parseArtifactName 은 requests-2.31.0-py3-none-any.whl, foo-bar-1.0.tar.gz 에서 이름과 버전을 꺼냅니다.
+func parseArtifactName(file string) (string, string, bool) {
+	if base, ok := strings.CutSuffix(file, ".whl"); ok {
+		parts := strings.Split(base, "-")
+		if len(parts) < 5 {
+			return "", "", false
+		}
+		return parts[0], parts[1], true
+	}
+	for _, ext := range []string{".tar.gz", ".zip"} {
+		if base, ok := strings.CutSuffix(file, ext); ok {
+			i := strings.LastIndex(base, "-")
+			if i <= 0 {
+				return "", "", false
+			}
+			return base[:i], base[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// offlineChecker 는 네트워크 없이 설치할 수 있는지 확인합니다.
+type offlineChecker struct {
+	installed map[string]*dist.Distribution
+	artifacts localArtifacts
+	replaced  map[string]string
+	missing   []string
+}
+
+func newOfflineChecker(replaced map[string]string) *offlineChecker {
+	dists, _ := dist.LoadVenv(venvDir())
+	return &offlineChecker{
+		installed: dist.Index(dists),
+		artifacts: scanLocalArtifacts(localFindLinks()),
+		replaced:  replaced,
+	}
+}
+
+// checkLocked 는 lock 항목이 venv 에 설치돼 있거나 로컬에 있는지 확인합니다.
+func (c *offlineChecker) checkLocked(p modfile.LockedPackage) {
+	name := dist.NormalizeName(p.Name)
+	if _, ok := c.replaced[name]; ok {
+		return
+	}
+	if vcs.IsURL(p.Source) {
+		ref, err := vcs.ParseURL(p.Source)
+		if err == nil && c.hasCommit(name, ref) {
+			return
+		}
+		c.missing = append(c.missing, p.Name+" @ "+p.Source)
+		return
+	}
+	if d, ok := c.installed[name]; ok && d.Version == p.Version {
+		return
+	}
+	for _, v := range c.artifacts[name] {
+		if dist.CompareVersions(v, p.Version) == 0 {
+			return
+		}
+	}
+	c.missing = append(c.missing, p.Name+"=="+p.Version)
+}
+
+// checkRequirement 는 requirement 를 만족하는 버전이 venv 나 로컬에 있는지 확인합니다.
+func (c *offlineChecker) checkRequirement(req dist.Requirement) {
+	name := dist.NormalizeName(req.Name)
+	if _, ok := c.replaced[name]; ok {
+		return
+	}
+	if req.URL != "" {
+		if vcs.IsURL(req.URL) {
+			if ref, err := vcs.ParseURL(req.URL); err == nil && c.hasCommit(name, ref) {
+				return
+			}
+		}
+		c.missing = append(c.missing, req.String())
+		return
+	}
+	if d, ok := c.installed[name]; ok && dist.MatchSpecifier(d.Version, req.Specifier) {
+		return
+	}
+	for _, v := range c.artifacts[name] {
+		if dist.MatchSpecifier(v, req.Specifier) {
+			return
+		}
+	}
+	c.missing = append(c.missing, req.String())
+}
+
+// hasCommit 은 ref 의 커밋이 venv 에 설치돼 있거나 캐시의 mirror 에 있는지 확인합니다.
+func (c *offlineChecker) hasCommit(name string, ref vcs.Ref) bool {
+	if d, ok := c.installed[name]; ok {
+		if installed, ok := installedVCSRef(d); ok && installed.Repo == ref.Repo {
+			if want, isCommit := vcs.CommitPrefix(ref.Rev); isCommit && strings.HasPrefix(installed.Rev, want) {
+				return true
+			}
+		}
+	}
+	_, err := vcs.ResolveCached(pigoCacheDir(), ref)
+	return err == nil
+}
+
+// err 는 빠진 항목이 있으면 목록과 함께 에러를 돌려줍니다.
+func (c *offlineChecker) err() error {
+	if len(c.missing) == 0 {
+		return nil
+	}
+	where := []string{displayPath(vendorDir()), filepath.Join(pigoCacheDir(), "wheels")}
+	return fmt.Errorf("offline: %d packages are not available locally:\n\t%s\n\nput them in %s (pigo vendor) or run without --offline",
+		len(c.missing), strings.Join(c.missing, "\n\t"), strings.Join(where, " or "))
+}
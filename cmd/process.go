@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// killGrace 는 timeout 뒤 자식에게 종료를 요청하고 강제로 끝내기까지 기다리는 시간입니다.
+const killGrace = 5 * time.Second
+
+// timeoutExitCode 는 timeout 으로 끝났을 때의 종료 코드입니다. (timeout(1) 과 같음)
+const timeoutExitCode = 124
+
+// exitError 는 자식 프로세스의 종료 코드로 pigo 를 끝내야 하는 에러입니다.
+// Err 가 nil 이면 자식이 이미 에러를 출력했으므로 pigo 는 아무것도 출력하지 않습니다.
+type exitError struct {
+	Code int
+	Err  error
+}
+
+func (e *exitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *exitError) Unwrap() error {
+	return e.Err
+}
+
+// fatal 은 err 를 출력하고 종료합니다. 자식 프로세스가 실패했다면 그 종료 코드를 그대로 씁니다.
+func fatal(err error) {
+	var ee *exitError
+	if errors.As(err, &ee) {
+		if ee.Err != nil {
+			log.Printf("error: %v", ee.Err)
+		}
+		os.Exit(ee.Code)
+	}
+	log.Fatalf("error: %v", err)
+}
+
+// runProcess 는 cmd 를 실행하고 끝날 때까지 기다립니다.
+// pigo 가 받은 SIGINT, SIGTERM, SIGHUP 은 보낸 곳과 상관없이 자식에게 전달합니다. (forwardSignal)
+// ctx 가 끝나면(timeout) 자식에게 SIGTERM 을 보내고, killGrace 뒤에도 살아 있으면 강제로 끝냅니다.
+// 자식이 0 이 아닌 코드나 시그널로 끝나면 *exitError 를 돌려줍니다. (시그널이면 128+번호)
+func runProcess(ctx context.Context, cmd *exec.Cmd) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, forwardedSignals...)
+	defer signal.Stop(sigs)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	ctxDone := ctx.Done()
+	var kill <-chan time.Time
+	for {
+		select {
+		case err := <-done:
+			if ctx.Err() == context.DeadlineExceeded {
+				return &exitError{Code: timeoutExitCode, Err: fmt.Errorf("%s: timed out", filepath.Base(cmd.Path))}
+			}
+			return exitStatus(err)
+		case sig := <-sigs:
+			forwardSignal(cmd.Process, sig)
+		case <-ctxDone:
+			ctxDone = nil
+			terminate(cmd.Process)
+			kill = time.After(killGrace)
+		case <-kill:
+			cmd.Process.Kill()
+		}
+	}
+}
+
+// exitStatus 는 cmd.Wait 의 에러를 *exitError 로 바꿉니다.
+func exitStatus(err error) error {
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		return err
+	}
+	if code := ee.ExitCode(); code >= 0 {
+		return &exitError{Code: code}
+	}
+	if sig, ok := exitSignal(ee); ok {
+		return &exitError{Code: 128 + sig}
+	}
+	return &exitError{Code: 1, Err: err}
+}
+
+// timeoutContext 는 d 뒤에 끝나는 context 입니다. d 가 0 이면 timeout 이 없습니다.
+func timeoutContext(d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), d)
+}
+
+// parseTimeout 은 --timeout 값을 읽습니다. 단위가 없으면 초입니다. (30, 90s, 5m)
+func parseTimeout(value string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --timeout=%s", value)
+	}
+	return d, nil
+}
+
+// cutTimeoutFlag 는 args[i] 가 --timeout 이면 값을 읽고 다음 인덱스를 돌려줍니다.
+func cutTimeoutFlag(args []string, i int) (time.Duration, int, bool, error) {
+	arg := args[i]
+	var value string
+	switch {
+	case arg == "--timeout":
+		if i+1 >= len(args) {
+			return 0, i, true, fmt.Errorf("flag needs an argument: --timeout")
+		}
+		i++
+		value = args[i]
+	case len(arg) > len("--timeout=") && arg[:len("--timeout=")] == "--timeout=":
+		value = arg[len("--timeout="):]
+	default:
+		return 0, i, false, nil
+	}
+	d, err := parseTimeout(value)
+	return d, i, true, err
+}
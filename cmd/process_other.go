@@ -0,0 +1,22 @@
+//go:build !unix
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+)
+
+// 콘솔의 Ctrl-C 는 자식에게도 전달되므로 pigo 는 받기만 하고 다시 보내지 않습니다.
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// os.Interrupt 는 다른 프로세스에 보낼 수 없습니다.
+func forwardSignal(p *os.Process, sig os.Signal) {}
+
+func terminate(p *os.Process) {
+	p.Kill()
+}
+
+func exitSignal(ee *exec.ExitError) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// forwardSignal 은 pigo 가 받은 sig 를 자식에게 보냅니다.
+// 터미널의 Ctrl-C 는 같은 프로세스 그룹의 자식도 이미 받았지만, syscall 로는 보낸 쪽(si_pid)을 알 수 없어
+// kill -INT <pigo pid> 와 구분할 수 없으므로 항상 보냅니다. 두 SIGINT 는 거의 동시에 도착하므로
+// python 은 보통 KeyboardInterrupt 한 번으로 처리합니다.
+func forwardSignal(p *os.Process, sig os.Signal) {
+	p.Signal(sig)
+}
+
+func terminate(p *os.Process) {
+	p.Signal(syscall.SIGTERM)
+}
+
+func exitSignal(ee *exec.ExitError) (int, bool) {
+	ws, ok := ee.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return 0, false
+	}
+	return int(ws.Signal()), true
+}
@@ -0,0 +1,50 @@
+//go:build unix
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunProcessForwardsExternalInterrupt(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	// 자식은 시작했다는 표시로 ready 를 닫고 SIGINT 를 받으면 7 로 끝납니다.
+	ready := t.TempDir() + "/ready"
+	cmd := exec.Command("sh", "-c", `trap 'exit 7' INT; : > "$0"; while :; do sleep 0.05; done`, ready)
+	done := make(chan error, 1)
+	go func() { done <- runProcess(context.Background(), cmd) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(ready); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("child did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// kill -INT <pigo pid> 처럼 터미널이 아닌 곳에서 pigo 에만 보낸 SIGINT 입니다.
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		var ee *exitError
+		if !errors.As(err, &ee) || ee.Code != 7 {
+			t.Errorf("runProcess = %v, want exit status 7 from the trapped SIGINT", err)
+		}
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Fatal("SIGINT was not forwarded to the child")
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	_const "github.com/janghanul090801/pigo/cmd/const"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+)
+
+// 프로젝트 루트를 알려주는 파일들 (가까운 디렉토리가 우선)
+var projectMarkers = []string{modfile.FileName, "pyproject.toml", "requirements.txt"}
+
+// findProjectRoot 는 start 에서 위로 올라가며 projectMarkers 중 하나가 있는
+// 가장 가까운 디렉토리를 찾습니다.
+func findProjectRoot(start string) (string, bool) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return start, false
+	}
+	for {
+		for _, marker := range projectMarkers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+var projectRootDir string
+
+// projectRoot 는 현재 디렉토리가 속한 프로젝트의 루트입니다.
+// 프로젝트를 찾지 못하면 현재 디렉토리를 사용합니다.
+func projectRoot() string {
+	if projectRootDir != "" {
+		return projectRootDir
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	if root, ok := findProjectRoot(cwd); ok {
+		projectRootDir = root
+	} else {
+		projectRootDir = cwd
+	}
+	return projectRootDir
+}
+
+// chdirProject 는 dir 로 이동하고 프로젝트 루트를 다시 찾게 합니다.
+func chdirProject(dir string) error {
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+	projectRootDir = ""
+	return nil
+}
+
+// projectPath 는 프로젝트 루트 기준 경로입니다.
+func projectPath(name string) string {
+	return filepath.Join(projectRoot(), name)
+}
+
+func venvDir() string {
+	return projectPath(".venv")
+}
+
+func pipPath() string {
+	return projectPath(_const.PIPPATH)
+}
+
+func pythonPath() string {
+	return projectPath(_const.PYTHONPATH)
+}
@@ -0,0 +1,586 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/spf13/cobra"
+)
+
+// Interpreter 는 시스템에서 찾은 python interpreter 입니다.
+type Interpreter struct {
+	Version string
+	Path    string
+	Source  string // pigo, pyenv, path, system
+}
+
+// pigoHome 은 pigo 가 interpreter 등을 설치하는 디렉토리입니다. (PIGO_HOME, 기본값 ~/.pigo)
+func pigoHome() string {
+	if home := os.Getenv("PIGO_HOME"); home != "" {
+		return home
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".pigo"
+	}
+	return filepath.Join(home, ".pigo")
+}
+
+// pigoCacheDir 는 wheel 등을 캐시하는 디렉토리입니다. (PIGO_CACHE, 기본값 사용자 캐시/pigo)
+func pigoCacheDir() string {
+	if dir := os.Getenv("PIGO_CACHE"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(pigoHome(), "cache")
+	}
+	return filepath.Join(dir, "pigo")
+}
+
+var (
+	interpreterNameRe = regexp.MustCompile(`^python(3(\.\d+)?)?(\.exe)?$`)
+	pythonVersionRe   = regexp.MustCompile(`Python (\d+\.\d+\.\d+)`)
+)
+
+// standalone 빌드 압축을 풀었을 때 interpreter 가 있을 수 있는 위치들
+var standaloneLayouts = []string{
+	"python/install/bin/python3",
+	"python/bin/python3",
+	"bin/python3",
+	"python/install/python.exe",
+	"python/python.exe",
+	"python.exe",
+}
+
+// matchesPin 은 3.12.1 이 3.12 나 3.12.1 pin 을 만족하는지 확인합니다.
+func matchesPin(version, pin string) bool {
+	return pin == "" || version == pin || strings.HasPrefix(version, pin+".")
+}
+
+// interpreterVersion 은 python --version 으로 버전을 읽습니다.
+func interpreterVersion(path string) (string, error) {
+	out, err := exec.Command(path, "--version").CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+	m := pythonVersionRe.FindStringSubmatch(string(out))
+	if m == nil {
+		return "", fmt.Errorf("%s: unexpected --version output %q", path, strings.TrimSpace(string(out)))
+	}
+	return m[1], nil
+}
+
+// findInterpreterIn 은 standalone 빌드가 설치된 디렉토리에서 interpreter 를 찾습니다.
+func findInterpreterIn(dir string) string {
+	for _, layout := range standaloneLayouts {
+		path := filepath.Join(dir, filepath.FromSlash(layout))
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// isInsideVenv 는 path 가 가상환경 안의 interpreter 인지 확인합니다.
+func isInsideVenv(path string) bool {
+	_, err := os.Stat(filepath.Join(filepath.Dir(filepath.Dir(path)), "pyvenv.cfg"))
+	return err == nil
+}
+
+// discoverInterpreters 는 pigo 설치본, pyenv, PATH, /usr/bin 에서 interpreter 를 찾습니다.
+// 같은 실행 파일은 한 번만 나오며 버전이 높은 순서로 정렬됩니다.
+func discoverInterpreters() []Interpreter {
+	type candidate struct{ path, source string }
+	var candidates []candidate
+
+	// 1. pigo python install 로 설치한 것
+	dirs, _ := filepath.Glob(filepath.Join(pigoHome(), "python", "*"))
+	for _, dir := range dirs {
+		if path := findInterpreterIn(dir); path != "" {
+			candidates = append(candidates, candidate{path, "pigo"})
+		}
+	}
+
+	// 2. pyenv
+	pyenvRoot := os.Getenv("PYENV_ROOT")
+	if pyenvRoot == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			pyenvRoot = filepath.Join(home, ".pyenv")
+		}
+	}
+	if pyenvRoot != "" {
+		paths, _ := filepath.Glob(filepath.Join(pyenvRoot, "versions", "*", "bin", "python"))
+		for _, path := range paths {
+			candidates = append(candidates, candidate{path, "pyenv"})
+		}
+	}
+
+	// 3. PATH (pyenv shim 은 실제 interpreter 가 아니므로 제외)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if strings.Contains(filepath.ToSlash(dir), "/.pyenv/shims") {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() && interpreterNameRe.MatchString(e.Name()) {
+				candidates = append(candidates, candidate{filepath.Join(dir, e.Name()), "path"})
+			}
+		}
+	}
+
+	// 4. 시스템 python3.X
+	if runtime.GOOS != "windows" {
+		for _, pattern := range []string{"/usr/bin/python3*", "/usr/local/bin/python3*"} {
+			paths, _ := filepath.Glob(pattern)
+			for _, path := range paths {
+				if interpreterNameRe.MatchString(filepath.Base(path)) {
+					candidates = append(candidates, candidate{path, "system"})
+				}
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var result []Interpreter
+	for _, c := range candidates {
+		real, err := filepath.EvalSymlinks(c.path)
+		if err != nil || seen[real] || isInsideVenv(c.path) {
+			continue
+		}
+		seen[real] = true
+		version, err := interpreterVersion(c.path)
+		if err != nil {
+			continue
+		}
+		result = append(result, Interpreter{Version: version, Path: c.path, Source: c.source})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return dist.CompareVersions(result[i].Version, result[j].Version) > 0
+	})
+	return result
+}
+
+// findInterpreter 는 pin 을 만족하는 가장 높은 버전의 interpreter 를 찾습니다.
+// pin 이 없으면 PATH 의 python 을 사용합니다.
+func findInterpreter(pin string) (Interpreter, error) {
+	if pin == "" {
+		for _, name := range []string{"python", "python3"} {
+			if path, err := exec.LookPath(name); err == nil {
+				version, err := interpreterVersion(path)
+				if err != nil {
+					return Interpreter{}, err
+				}
+				return Interpreter{Version: version, Path: path, Source: "path"}, nil
+			}
+		}
+		return Interpreter{}, fmt.Errorf("python not found in PATH")
+	}
+	for _, interp := range discoverInterpreters() {
+		if matchesPin(interp.Version, pin) {
+			return interp, nil
+		}
+	}
+	return Interpreter{}, fmt.Errorf("python %s not found (see 'pigo python list' or 'pigo python install')", pin)
+}
+
+// installInterpreter 는 python-build-standalone 압축 파일(로컬 경로 또는 URL)을
+// pigoHome()/python/cpython-<version> 에 풉니다.
+func installInterpreter(src string) (Interpreter, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		if offlineMode() {
+			return Interpreter{}, fmt.Errorf("offline: cannot download %s; pass a local archive instead", src)
+		}
+		downloaded, err := downloadFile(src)
+		if err != nil {
+			return Interpreter{}, err
+		}
+		defer os.Remove(downloaded)
+		src = downloaded
+	}
+
+	base := filepath.Join(pigoHome(), "python")
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return Interpreter{}, err
+	}
+	tmp, err := os.MkdirTemp(base, ".install-")
+	if err != nil {
+		return Interpreter{}, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extractArchive(src, tmp); err != nil {
+		return Interpreter{}, err
+	}
+	path := findInterpreterIn(tmp)
+	if path == "" {
+		return Interpreter{}, fmt.Errorf("%s: no python interpreter found in archive", src)
+	}
+	version, err := interpreterVersion(path)
+	if err != nil {
+		return Interpreter{}, err
+	}
+
+	dest := filepath.Join(base, "cpython-"+version)
+	if _, err := os.Stat(dest); err == nil {
+		return Interpreter{}, fmt.Errorf("python %s is already installed in %s", version, dest)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return Interpreter{}, err
+	}
+	rel, _ := filepath.Rel(tmp, path)
+	return Interpreter{Version: version, Path: filepath.Join(dest, rel), Source: "pigo"}, nil
+}
+
+// downloadClient 는 python 배포판을 받을 때 씁니다. 응답이 없는 서버에서 멈추지 않도록 제한 시간을 둡니다.
+var downloadClient = &http.Client{Timeout: 10 * time.Minute}
+
+func downloadFile(url string) (string, error) {
+	resp, err := downloadClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+	f, err := os.CreateTemp("", "pigo-download-*"+filepath.Ext(url))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// extractArchive 는 .tar.gz / .tgz / .tar / .zip 을 dest 에 풉니다.
+func extractArchive(src, dest string) error {
+	switch {
+	case strings.HasSuffix(src, ".zip"):
+		return extractZip(src, dest)
+	case strings.HasSuffix(src, ".tar.gz"), strings.HasSuffix(src, ".tgz"), strings.HasSuffix(src, ".tar"):
+		return extractTar(src, dest)
+	}
+	return fmt.Errorf("%s: unsupported archive format (use .tar.gz or .zip)", src)
+}
+
+// safeJoin 은 압축 안의 경로가 dest 밖으로 나가지 않는지 확인합니다.
+func safeJoin(dest, name string) (string, error) {
+	path := filepath.Join(dest, filepath.FromSlash(name))
+	if path != dest && !strings.HasPrefix(path, filepath.Clean(dest)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return path, nil
+}
+
+func extractTar(src, dest string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(src, ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	// 모든 항목은 os.Root 로 씁니다. 앞서 푼 링크를 따라 dest 밖으로 나가는 경로는 실패합니다.
+	root, err := os.OpenRoot(dest)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return checkSymlinks(dest)
+		} else if err != nil {
+			return err
+		}
+		if _, err := safeJoin(dest, hdr.Name); err != nil {
+			return err
+		}
+		name := filepath.FromSlash(hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := root.MkdirAll(name, 0755); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// 링크가 가리키는 곳도 dest 안이어야 합니다. 디스크의 다른 링크를 거치는 경우는 checkSymlinks 가 확인합니다.
+			if filepath.IsAbs(hdr.Linkname) || filepath.VolumeName(hdr.Linkname) != "" {
+				return fmt.Errorf("illegal symlink in archive: %s -> %s", hdr.Name, hdr.Linkname)
+			}
+			if _, err := safeJoin(dest, filepath.Join(filepath.Dir(name), filepath.FromSlash(hdr.Linkname))); err != nil {
+				return fmt.Errorf("illegal symlink in archive: %s -> %s", hdr.Name, hdr.Linkname)
+			}
+			if err := root.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				return err
+			}
+			if err := root.Symlink(hdr.Linkname, name); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			if _, err := safeJoin(dest, hdr.Linkname); err != nil {
+				return err
+			}
+			if err := root.Link(filepath.FromSlash(hdr.Linkname), name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeRootFile(root, name, tr, os.FileMode(hdr.Mode)&0777); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkSymlinks 는 dest 안의 모든 symlink 가 dest 안을 가리키는지 확인합니다.
+// y -> . 다음 x -> y/.. 처럼 링크를 거쳐 밖으로 나가는 경우는 경로 문자열만으로 알 수 없으므로
+// 다 푼 뒤 실제로 따라가 봅니다. 가리키는 곳이 없는 링크도 거부합니다.
+func checkSymlinks(dest string) error {
+	realDest, err := filepath.EvalSymlinks(dest)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(dest, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.Type()&fs.ModeSymlink == 0 {
+			return err
+		}
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil || (target != realDest && !strings.HasPrefix(target, realDest+string(os.PathSeparator))) {
+			link, _ := os.Readlink(path)
+			rel, _ := filepath.Rel(dest, path)
+			return fmt.Errorf("illegal symlink in archive: %s -> %s", filepath.ToSlash(rel), link)
+		}
+		return nil
+	})
+}
+
+func extractZip(src, dest string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		path, err := safeJoin(dest, zf.Name)
+		if err != nil {
+			return err
+		}
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		mode := zf.Mode() & 0777
+		if mode == 0 {
+			mode = 0644
+		}
+		err = writeFile(path, rc, mode)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// writeRootFile 은 root 안의 name 에 r 의 내용을 씁니다.
+func writeRootFile(root *os.Root, name string, r io.Reader, mode os.FileMode) error {
+	if err := root.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	out, err := root.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// recreateVenv 는 .venv 를 지우고 interp 로 다시 만든 뒤 requirements.txt 를 다시 설치합니다.
+func recreateVenv(interp Interpreter) error {
+	if err := os.RemoveAll(venvDir()); err != nil {
+		return err
+	}
+	if err := createVenv(venvDir(), interp, true, os.Stdout); err != nil {
+		return err
+	}
+	reqPath := projectPath("requirements.txt")
+	if _, err := os.Stat(reqPath); err != nil {
+		return nil
+	}
+	pipArgs := []string{"install", "-r", reqPath}
+	if offlineMode() {
+		pipArgs = append(pipArgs, offlinePipArgs()...)
+	}
+	pipCmd := exec.Command(pipPath(), pipArgs...)
+	pipCmd.Stdout = os.Stdout
+	pipCmd.Stderr = os.Stderr
+	if offlineMode() {
+		pipCmd.Env = offlineEnv()
+	}
+	return runProcess(context.Background(), pipCmd)
+}
+
+var pythonCmd = &cobra.Command{
+	Use:   "python",
+	Short: "Manage python interpreters",
+	Long: `Discover installed python interpreters, pin the project to a version in pigo.mod
+and install standalone python builds from a local archive.`,
+}
+
+var pythonListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed python interpreters",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mf, err := modfile.Read(projectPath(modfile.FileName))
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+
+		interps := discoverInterpreters()
+		if len(interps) == 0 {
+			fmt.Println("No python interpreters found.")
+			return
+		}
+
+		// pin 이 있으면 실제로 선택될 interpreter 에 * 표시
+		selected := ""
+		if mf.Python != "" {
+			for _, interp := range interps {
+				if matchesPin(interp.Version, mf.Python) {
+					selected = interp.Path
+					break
+				}
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, interp := range interps {
+			mark := " "
+			if interp.Path == selected {
+				mark = "*"
+			}
+			fmt.Fprintf(w, "%s %s\t%s\t%s\n", mark, interp.Version, interp.Path, interp.Source)
+		}
+		w.Flush()
+	},
+}
+
+var pythonUseCmd = &cobra.Command{
+	Use:   "use <version>",
+	Short: "Pin the project to a python version",
+	Long: `Records 'python <version>' in pigo.mod. If .venv was created with a different
+version, it is recreated with the pinned interpreter and requirements.txt is reinstalled.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pin := args[0]
+		interp, err := findInterpreter(pin)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+
+		mf, err := modfile.Read(projectPath(modfile.FileName))
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		mf.SetPython(pin)
+		if err := mf.Write(projectPath(modfile.FileName)); err != nil {
+			log.Fatalf("error writing %s: %v", modfile.FileName, err)
+		}
+		fmt.Printf("python %s (%s at %s)\n", pin, interp.Version, interp.Path)
+
+		if _, err := os.Stat(venvDir()); err != nil {
+			return
+		}
+		current, err := dist.VenvPythonVersion(venvDir())
+		if err == nil && matchesPin(current, pin) {
+			return
+		}
+		fmt.Printf("Recreating .venv with python %s...\n", interp.Version)
+		if err := recreateVenv(interp); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	},
+}
+
+var pythonInstallCmd = &cobra.Command{
+	Use:   "install <archive>",
+	Short: "Install a standalone python build",
+	Long: `Installs a python-build-standalone archive (.tar.gz or .zip) into ~/.pigo/python
+(or $PIGO_HOME/python). The archive may be a local path, so no network access is needed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		interp, err := installInterpreter(args[0])
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		fmt.Printf("Installed python %s at %s\n", interp.Version, interp.Path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pythonCmd)
+	pythonCmd.AddCommand(pythonListCmd)
+	pythonCmd.AddCommand(pythonUseCmd)
+	pythonCmd.AddCommand(pythonInstallCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarEntry 는 테스트용 압축 파일의 한 항목입니다. link 가 있으면 symlink (hard 면 hardlink) 입니다.
+type tarEntry struct {
+	name, body, link string
+	hard             bool
+}
+
+// writeTarGz 는 entries 를 순서대로 담은 .tar.gz 를 만듭니다.
+func writeTarGz(t *testing.T, entries []tarEntry) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "python.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		switch {
+		case e.hard:
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeLink, e.link, 0
+		case e.link != "":
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, e.link, 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, c := range []interface{ Close() error }{tw, gz, f} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestExtractTar(t *testing.T) {
+	dest := t.TempDir()
+	archive := writeTarGz(t, []tarEntry{
+		{name: "python/bin/python3.12", body: "#!"},
+		{name: "python/bin/python3", link: "python3.12"},
+		{name: "python/lib/libpython.so", body: "elf"},
+		{name: "python/lib64", link: "lib"},
+		{name: "python/lib64/extra.txt", body: "via link"},
+		{name: "python/bin/python", link: "python/bin/python3.12", hard: true},
+	})
+	if err := extractTar(archive, dest); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"python/bin/python3":        "#!",
+		"python/bin/python":         "#!",
+		"python/lib/extra.txt":      "via link",
+		"python/lib64/libpython.so": "elf",
+	} {
+		data, err := os.ReadFile(filepath.Join(dest, name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+}
+
+func TestExtractTarRejectsEscapes(t *testing.T) {
+	tests := map[string][]tarEntry{
+		"parent":          {{name: "../evil", body: "x"}},
+		"absolute link":   {{name: "evil", link: "/tmp"}},
+		"parent link":     {{name: "a/evil", link: "../../tmp"}},
+		"hardlink":        {{name: "evil", link: "../outside", hard: true}},
+		"link chain":      {{name: "y", link: "."}, {name: "evil", link: "y/.."}},
+		"write via chain": {{name: "y", link: "."}, {name: "x", link: "y/.."}, {name: "x/evil", body: "x"}},
+		"dangling chain":  {{name: "y", link: "."}, {name: "evil", link: "y/../missing"}},
+	}
+	for name, entries := range tests {
+		t.Run(name, func(t *testing.T) {
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "dest")
+			if err := os.Mkdir(dest, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := extractTar(writeTarGz(t, entries), dest); err == nil {
+				t.Fatal("extractTar succeeded, want error")
+			}
+			if _, err := os.Lstat(filepath.Join(parent, "evil")); err == nil {
+				t.Error("extractTar wrote outside dest")
+			}
+		})
+	}
+}
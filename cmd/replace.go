@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+)
+
+// replacements 는 root 의 pigo.mod 에 있는 replace 지시어를
+// 정규화된 패키지 이름 -> 절대경로 로 돌려줍니다.
+func replacements(root string) (map[string]string, error) {
+	mf, err := modfile.Read(filepath.Join(root, modfile.FileName))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	for _, r := range mf.Replace {
+		path := r.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		result[dist.NormalizeName(r.Name)] = abs
+	}
+	return result, nil
+}
+
+// replaceInstallArgs 는 pip install 인자 중 replace 된 패키지를 -e <path> 로 바꿉니다.
+func replaceInstallArgs(args []string, replaced map[string]string) []string {
+	var result []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			if req, ok := modfile.ParseRequirementLine(arg); ok {
+				if dir, ok := replaced[dist.NormalizeName(req.Name)]; ok {
+					result = append(result, "-e", dir+extrasSuffix(req.Extras))
+					continue
+				}
+			}
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+func extrasSuffix(extras []string) string {
+	if len(extras) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%s]", strings.Join(extras, ","))
+}
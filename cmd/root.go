@@ -5,13 +5,13 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-
-
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "pigo",
@@ -25,27 +25,77 @@ to quickly create a Cobra application.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// 플래그 파싱을 하는 명령에서는 -C 가 서브커맨드 뒤에 와도 cobra 가 읽어줍니다.
+		if on, _ := cmd.Flags().GetBool("offline"); on {
+			offline = true
+		}
+		if dir, _ := cmd.Flags().GetString("chdir"); dir != "" {
+			return chdirProject(dir)
+		}
+		return nil
+	},
 }
 
+// offline 은 --offline 이 주어졌는지입니다. offlineMode 로 확인합니다.
+var offline bool
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	args, err := applyGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	rootCmd.SetArgs(args)
+
+	err = rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
+// applyGlobalFlags 는 서브커맨드 앞에 오는 전역 플래그(-C dir, --offline)를 먼저 처리하고 나머지 인자를 돌려줍니다.
+// run, install 처럼 플래그 파싱을 끈 명령은 cobra 가 전역 플래그를 읽지 못하므로 여기서 처리합니다.
+func applyGlobalFlags(args []string) ([]string, error) {
+	for len(args) > 0 {
+		arg := args[0]
+		switch {
+		case arg == "-C" || arg == "--chdir":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("flag needs an argument: %s", arg)
+			}
+			if err := chdirProject(args[1]); err != nil {
+				return nil, err
+			}
+			args = args[2:]
+		case strings.HasPrefix(arg, "-C=") || strings.HasPrefix(arg, "--chdir="):
+			_, dir, _ := strings.Cut(arg, "=")
+			if err := chdirProject(dir); err != nil {
+				return nil, err
+			}
+			args = args[1:]
+		case arg == "--offline":
+			offline = true
+			args = args[1:]
+		default:
+			return args, nil
+		}
+	}
+	return args, nil
+}
+
 func init() {
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.pigo.yaml)")
+	rootCmd.PersistentFlags().StringP("chdir", "C", "", "Change to dir before running the command (for run, install, uninstall, exec and test it must come before the command)")
+	rootCmd.PersistentFlags().Bool("offline", false, "Never use the network; use only vendor/, the pigo cache and the venv (also PIGO_OFFLINE=1)")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
-
-
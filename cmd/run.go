@@ -4,37 +4,408 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	_const "github.com/janghanul090801/pigo/cmd/const"
-	"github.com/spf13/cobra"
+	"fmt"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/spf13/cobra"
 )
 
 // runCmd represents the run command
 var runCmd = &cobra.Command{
-	Use:   "run",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Use:   "run [-m module | script | file.py] [args...] [-- args...]",
+	Short: "Run a Python file, module or script in the virtual environment",
+	Long: `Run a Python file, module or script with the virtual environment.
+
+  pigo run app.py [args]       run a file with .venv python
+  pigo run -m module [args]    run a module (python -m)
+  pigo run <script> [args]     run a script declared in pigo.mod
+  pigo run <command> [args]    run a console script installed in .venv
+
+pigo.mod is a line-based file like go.mod, not TOML, so scripts are declared
+with the script directive rather than a [scripts] table:
+
+  script test = pytest -q
+  script (
+      serve = uvicorn app:app --reload
+  )
+
+Before running, .venv is created if it is missing (or was made with a python
+that does not match pigo.mod) and locked packages that are missing or differ
+are installed from pigo.lock. Packages that are not locked (pytest, ipython...)
+are left alone; use pigo sync to remove them. --no-sync skips this. With
+--offline only vendor/, the pigo cache and the installed .venv are used.
+
+The process gets VIRTUAL_ENV and PATH like an activated venv, plus variables
+from pigo.mod (env NAME=value, env <script> NAME=value), .env in the project
+root and each --env-file (later files win; variables already set in the
+environment are never overridden).
+
+pigo exits with the exit code of the program. SIGINT, SIGTERM and SIGHUP are
+forwarded to it, and --timeout 30s stops it (exit code 124) if it runs longer.
+
+With --watch the program is restarted whenever a .py file in the project
+changes (.venv and paths in .gitignore are ignored).
+
+Arguments after -- are passed through untouched. Flags before the target
+that pigo does not know are passed to python.`,
 	DisableFlagParsing: true,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		runCmd := exec.Command(_const.PYTHONPATHWINDOW, args...)
-		runCmd.Stdout = os.Stdout
-		runCmd.Stderr = os.Stderr
-		runCmd.Stdin = os.Stdin
-
-		if err := runCmd.Run(); err != nil {
+		opts, args, err := parseRunFlags(args)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		if opts.Help {
+			cmd.Help()
+			return
+		}
+		if !opts.NoSync {
+			if err := prepareRunEnv(); err != nil {
+				log.Fatalf("error: %v", err)
+			}
+		}
+		name, argv, err := runCommand(opts, args)
+		if err != nil {
 			log.Fatalf("error: %v", err)
 		}
+		env, err := runEnvironment(scriptName(opts, args), opts.EnvFiles)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+
+		newCmd := func() *exec.Cmd {
+			runCmd := exec.Command(name, argv...)
+			runCmd.Stdout = os.Stdout
+			runCmd.Stderr = os.Stderr
+			runCmd.Stdin = os.Stdin
+			runCmd.Env = env
+			return runCmd
+		}
+		if opts.Watch {
+			fatal(runWatch(newCmd))
+		}
+
+		ctx, cancel := timeoutContext(opts.Timeout)
+		defer cancel()
+		if err := runProcess(ctx, newCmd()); err != nil {
+			fatal(err)
+		}
 	},
 }
 
+// runOptions 는 실행 대상 앞에 오는 pigo run 옵션입니다.
+type runOptions struct {
+	Module     string   // -m 으로 실행할 모듈
+	PythonArgs []string // pigo 가 모르는 옵션은 python 에 넘깁니다. (-u, -X dev 등)
+	NoSync     bool     // .venv 를 만들거나 pigo.lock 과 맞추지 않음
+	EnvFiles   []string // --env-file 로 추가로 읽을 .env 파일
+	Timeout    time.Duration
+	Watch      bool // .py 파일이 바뀌면 다시 실행
+	Help       bool
+}
+
+// parseRunFlags 는 실행 대상 앞의 옵션을 읽고, 실행 대상과 그 인자를 돌려줍니다.
+// 대상 바로 뒤의 첫 -- 는 지웁니다.
+func parseRunFlags(args []string) (runOptions, []string, error) {
+	var opts runOptions
+	i := 0
+loop:
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if d, next, ok, err := cutTimeoutFlag(args, i); ok {
+			if err != nil {
+				return opts, nil, err
+			}
+			opts.Timeout, i = d, next
+			continue
+		}
+		switch {
+		case arg == "--":
+			i++
+			break loop
+		case arg == "--no-sync":
+			opts.NoSync = true
+		case arg == "--watch":
+			opts.Watch = true
+		case arg == "--offline":
+			// 플래그 파싱을 끈 명령이므로 서브커맨드 뒤의 전역 플래그도 여기서 읽습니다.
+			offline = true
+		case arg == "--env-file":
+			if i+1 >= len(args) {
+				return opts, nil, fmt.Errorf("flag needs an argument: --env-file")
+			}
+			i++
+			opts.EnvFiles = append(opts.EnvFiles, args[i])
+		case strings.HasPrefix(arg, "--env-file="):
+			opts.EnvFiles = append(opts.EnvFiles, strings.TrimPrefix(arg, "--env-file="))
+		case arg == "-h" || arg == "--help":
+			opts.Help = true
+			return opts, nil, nil
+		case arg == "-m":
+			if i+1 >= len(args) {
+				return opts, nil, fmt.Errorf("flag needs an argument: -m")
+			}
+			opts.Module = args[i+1]
+			return opts, dropSeparator(args[i+2:]), nil
+		case strings.HasPrefix(arg, "-m") && !strings.HasPrefix(arg, "--"):
+			opts.Module = arg[2:]
+			return opts, dropSeparator(args[i+1:]), nil
+		case arg == "-c":
+			// python -c 는 뒤의 인자를 모두 python 에 넘깁니다.
+			opts.PythonArgs = append(opts.PythonArgs, args[i:]...)
+			return opts, nil, nil
+		case arg == "-X" || arg == "-W":
+			// 값을 다음 인자로 받는 python 옵션
+			opts.PythonArgs = append(opts.PythonArgs, arg)
+			if i+1 < len(args) {
+				i++
+				opts.PythonArgs = append(opts.PythonArgs, args[i])
+			}
+		case strings.HasPrefix(arg, "-"):
+			opts.PythonArgs = append(opts.PythonArgs, arg)
+		default:
+			break loop
+		}
+	}
+	rest := args[i:]
+	if len(rest) > 0 {
+		rest = append([]string{rest[0]}, dropSeparator(rest[1:])...)
+	}
+	return opts, rest, nil
+}
+
+// dropSeparator 는 맨 앞의 -- 하나를 지웁니다.
+func dropSeparator(args []string) []string {
+	if len(args) > 0 && args[0] == "--" {
+		return args[1:]
+	}
+	return args
+}
+
+// runCommand 는 실행할 프로그램과 인자를 정합니다.
+// 대상은 pigo.mod 의 script, 파일, .venv 의 console script 순서로 찾습니다.
+func runCommand(opts runOptions, args []string) (string, []string, error) {
+	python := pythonPath()
+	if opts.Module != "" {
+		return python, append(append(opts.PythonArgs, "-m", opts.Module), args...), nil
+	}
+	if len(args) == 0 {
+		return python, opts.PythonArgs, nil
+	}
+	target, rest := args[0], args[1:]
+
+	mf, err := modfile.Read(projectPath(modfile.FileName))
+	if err != nil {
+		return "", nil, err
+	}
+	if script, ok := mf.FindScript(target); ok {
+		words, err := splitCommandLine(script.Command)
+		if err != nil {
+			return "", nil, fmt.Errorf("script %s: %w", target, err)
+		}
+		name, err := venvExecutable(words[0])
+		if err != nil {
+			return "", nil, fmt.Errorf("script %s: %w", target, err)
+		}
+		return name, append(words[1:], rest...), nil
+	}
+
+	if _, err := os.Stat(target); err == nil || strings.HasSuffix(target, ".py") {
+		return python, append(append(opts.PythonArgs, target), rest...), nil
+	}
+
+	if ep, ok := findConsoleScript(target); ok {
+		if path := filepath.Join(venvBinDir(), target); isExecutable(path) {
+			return path, rest, nil
+		}
+		// 실행 파일이 없으면 (--no-deps 로 옮긴 venv 등) entry point 를 직접 부릅니다.
+		return python, append(append(opts.PythonArgs, "-c", entryPointScript(ep), target), rest...), nil
+	}
+	if path := filepath.Join(venvBinDir(), target); isExecutable(path) {
+		return path, rest, nil
+	}
+	return "", nil, fmt.Errorf("%s: no such file, script in %s or command in .venv", target, modfile.FileName)
+}
+
+// scriptName 은 실행 대상이 pigo.mod 의 script 이면 그 이름입니다.
+func scriptName(opts runOptions, args []string) string {
+	if opts.Module != "" || len(args) == 0 {
+		return ""
+	}
+	mf, err := modfile.Read(projectPath(modfile.FileName))
+	if err != nil {
+		return ""
+	}
+	if _, ok := mf.FindScript(args[0]); ok {
+		return args[0]
+	}
+	return ""
+}
+
+// prepareRunEnv 는 실행 전에 .venv 를 준비합니다.
+// .venv 가 없거나 pigo.mod 의 python 과 버전이 다르면 새로 만들고, pigo.lock 에 있는 패키지가
+// 빠졌거나 다르면 설치합니다. lock 에 없는 패키지(pytest, ipython 같은 도구)는 지우지 않습니다.
+// pigo.lock 이 없으면 새로 만든 .venv 에만 requirements.txt 를 설치합니다.
+// 실행할 프로그램의 출력과 섞이지 않도록 준비 과정의 출력은 stderr 로 보냅니다.
+func prepareRunEnv() error {
+	out := os.Stderr
+	mf, err := modfile.Read(projectPath(modfile.FileName))
+	if err != nil {
+		return err
+	}
+	created := false
+	if _, err := os.Stat(pythonPath()); err != nil {
+		created = true
+	} else if mf.Python != "" {
+		if current, err := dist.VenvPythonVersion(venvDir()); err != nil || !matchesPin(current, mf.Python) {
+			created = true
+		}
+	}
+	if created {
+		interp, err := findInterpreter(mf.Python)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Creating .venv with python %s...\n", interp.Version)
+		if err := os.RemoveAll(venvDir()); err != nil {
+			return err
+		}
+		if err := createVenv(venvDir(), interp, true, out); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(lockPath()); err != nil {
+		if !created {
+			return nil
+		}
+		return runInstall(nil, installOptions{Mod: "mod", Out: out})
+	}
+	lock, err := readLock()
+	if err != nil {
+		return err
+	}
+	dists, err := dist.LoadVenv(venvDir())
+	if err != nil {
+		return err
+	}
+	replaced, err := replacements(projectRoot())
+	if err != nil {
+		return err
+	}
+	plan := planSync(lock, dist.Index(dists), replaced)
+	plan.Uninstall = nil
+	if plan.empty() {
+		return nil
+	}
+	fmt.Fprintf(out, ".venv does not match %s; syncing (use --no-sync to skip)\n", modfile.LockFileName)
+	return runSync(syncOptions{Out: out})
+}
+
+// venvBinDir 는 가상환경의 실행 파일 디렉토리입니다.
+func venvBinDir() string {
+	return filepath.Dir(pythonPath())
+}
+
+// venvExecutable 은 name 을 .venv 의 실행 파일에서 먼저 찾고, 없으면 PATH 에서 찾습니다.
+func venvExecutable(name string) (string, error) {
+	if name == "python" || name == "python3" {
+		return pythonPath(), nil
+	}
+	if strings.ContainsRune(name, filepath.Separator) {
+		return name, nil
+	}
+	if path := filepath.Join(venvBinDir(), name); isExecutable(path) {
+		return path, nil
+	}
+	return exec.LookPath(name)
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Mode()&0111 != 0
+}
+
+// findConsoleScript 는 .venv 에 설치된 패키지의 console_scripts/gui_scripts 에서 name 을 찾습니다.
+func findConsoleScript(name string) (dist.EntryPoint, bool) {
+	dists, err := dist.LoadVenv(venvDir())
+	if err != nil {
+		return dist.EntryPoint{}, false
+	}
+	for _, d := range dists {
+		for _, ep := range d.EntryPoints {
+			if (ep.Group == "console_scripts" || ep.Group == "gui_scripts") && ep.Name == name {
+				return ep, true
+			}
+		}
+	}
+	return dist.EntryPoint{}, false
+}
+
+// entryPointScript 는 "module:attr.attr [extra]" 형식의 entry point 를 호출하는 python 코드입니다.
+func entryPointScript(ep dist.EntryPoint) string {
+	value, _, _ := strings.Cut(ep.Value, "[")
+	module, attr, _ := strings.Cut(strings.TrimSpace(value), ":")
+	// python -c 의 sys.argv[0] 은 "-c" 이므로 뒤에 넘긴 명령 이름을 argv[0] 으로 씁니다.
+	code := fmt.Sprintf("import sys, importlib\nsys.argv = sys.argv[1:]\nobj = importlib.import_module(%q)\n", strings.TrimSpace(module))
+	if attr = strings.TrimSpace(attr); attr != "" {
+		code += fmt.Sprintf("for a in %q.split('.'):\n    obj = getattr(obj, a)\nsys.exit(obj())\n", attr)
+	}
+	return code
+}
+
+// splitCommandLine 은 셸처럼 공백으로 명령을 나눕니다. 따옴표와 \ 이스케이프만 해석합니다.
+func splitCommandLine(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return words, nil
+}
+
 func init() {
 	rootCmd.AddCommand(runCmd)
 
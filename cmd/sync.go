@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/janghanul090801/pigo/cmd/vcs"
+	"github.com/spf13/cobra"
+)
+
+// syncProtected 는 lock 에 없어도 sync 가 지우지 않는 패키지입니다.
+var syncProtected = map[string]bool{
+	"pip":        true,
+	"setuptools": true,
+	"wheel":      true,
+}
+
+// syncChange 는 venv 를 lock 에 맞추기 위한 변경 하나입니다.
+type syncChange struct {
+	Name      string
+	Installed string // 설치된 버전 (없으면 "")
+	Locked    string // lock 의 버전 (지울 때는 "")
+	Spec      string // pip install 에 넘길 인자
+	Editable  bool   // Spec 을 -e 로 설치
+}
+
+// syncPlan 은 venv 와 pigo.lock 의 차이입니다.
+type syncPlan struct {
+	Install   []syncChange // venv 에 없는 패키지
+	Update    []syncChange // 버전이나 커밋이 다른 패키지
+	Uninstall []syncChange // lock 에 없는 패키지
+}
+
+func (p *syncPlan) empty() bool {
+	return len(p.Install) == 0 && len(p.Update) == 0 && len(p.Uninstall) == 0
+}
+
+// planSync 는 installed 를 lock 과 같게 만들기 위한 변경을 계산합니다.
+func planSync(lock *modfile.LockFile, installed map[string]*dist.Distribution, replaced map[string]string) *syncPlan {
+	plan := &syncPlan{}
+	locked := make(map[string]bool)
+	for _, p := range lock.Packages {
+		name := dist.NormalizeName(p.Name)
+		locked[name] = true
+		change := syncChange{Name: p.Name, Locked: p.Version, Spec: p.Name + "==" + p.Version}
+
+		d, ok := installed[name]
+		if ok {
+			change.Installed = d.Version
+		}
+		upToDate := ok && d.Version == p.Version
+		if dir, isReplaced := replaced[name]; isReplaced {
+			change.Spec, change.Editable = dir, true
+			upToDate = ok && d.Editable == dir
+		} else if ref, err := vcs.ParseURL(p.Source); err == nil {
+			change.Spec = dist.Requirement{Name: p.Name, URL: pipRef(ref).RequirementURL()}.String()
+			upToDate = false
+			if ok {
+				if installedRef, isVCS := installedVCSRef(d); isVCS {
+					change.Installed = installedRef.Rev[:min(12, len(installedRef.Rev))]
+					upToDate = installedRef.Repo == ref.Repo && installedRef.Rev == ref.Rev
+				}
+			}
+		}
+
+		switch {
+		case !ok:
+			plan.Install = append(plan.Install, change)
+		case !upToDate:
+			plan.Update = append(plan.Update, change)
+		}
+	}
+
+	names := make([]string, 0, len(installed))
+	for name := range installed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !locked[name] && !syncProtected[name] {
+			d := installed[name]
+			plan.Uninstall = append(plan.Uninstall, syncChange{Name: d.Name, Installed: d.Version})
+		}
+	}
+	return plan
+}
+
+func (p *syncPlan) print(w io.Writer) {
+	for _, c := range p.Install {
+		fmt.Fprintf(w, "+ %s %s\n", c.Name, c.Locked)
+	}
+	for _, c := range p.Update {
+		fmt.Fprintf(w, "~ %s %s -> %s\n", c.Name, c.Installed, c.Locked)
+	}
+	for _, c := range p.Uninstall {
+		fmt.Fprintf(w, "- %s %s\n", c.Name, c.Installed)
+	}
+}
+
+// syncOptions 는 runSync 의 옵션입니다.
+type syncOptions struct {
+	DryRun bool
+	Prune  bool      // lock 에 없는 패키지를 지웁니다. (pigo sync)
+	Out    io.Writer // 변경 목록과 pip 의 출력 (nil 이면 stdout)
+}
+
+// runSync 는 현재 프로젝트의 venv 를 pigo.lock 에 맞춥니다. Prune 이 아니면
+// 빠지거나 다른 lock 패키지만 설치하고 lock 에 없는 패키지는 그대로 둡니다.
+func runSync(opts syncOptions) error {
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	if _, err := os.Stat(lockPath()); os.IsNotExist(err) {
+		return fmt.Errorf("%s not found; run 'pigo install' first", modfile.LockFileName)
+	}
+	lock, err := readLock()
+	if err != nil {
+		return err
+	}
+	dists, err := dist.LoadVenv(venvDir())
+	if err != nil {
+		return fmt.Errorf("reading installed packages: %w", err)
+	}
+	replaced, err := replacements(projectRoot())
+	if err != nil {
+		return err
+	}
+
+	plan := planSync(lock, dist.Index(dists), replaced)
+	if !opts.Prune {
+		plan.Uninstall = nil
+	}
+	if plan.empty() {
+		fmt.Fprintln(out, "Already in sync.")
+		return nil
+	}
+	plan.print(out)
+	if opts.DryRun {
+		return nil
+	}
+
+	if offlineMode() {
+		c := newOfflineChecker(replaced)
+		for _, change := range append(plan.Install, plan.Update...) {
+			if p, ok := lock.Find(change.Name); ok {
+				c.checkLocked(p)
+			}
+		}
+		if err := c.err(); err != nil {
+			return err
+		}
+	}
+
+	// lock 은 이미 전이적으로 닫혀 있으므로 의존성은 다시 해석하지 않습니다.
+	// 다른 커밋의 git 패키지는 버전이 같아 pip 이 다시 설치하지 않으므로 --force-reinstall 을 씁니다.
+	// 미리 지우지 않으므로 설치가 실패하면 pip 이 기존 버전을 되돌려 둡니다.
+	installArgs := []string{"install", "--no-deps"}
+	if len(plan.Update) > 0 {
+		installArgs = append(installArgs, "--force-reinstall")
+	}
+	if offlineMode() {
+		installArgs = append(installArgs, offlinePipArgs()...)
+	}
+	changes := append(plan.Install, plan.Update...)
+	for _, c := range changes {
+		if c.Editable {
+			installArgs = append(installArgs, "-e")
+		}
+		installArgs = append(installArgs, c.Spec)
+	}
+	if len(changes) > 0 {
+		if err := runPipTo(out, installArgs...); err != nil {
+			return err
+		}
+	}
+
+	// lock 에 없는 패키지는 설치가 끝난 뒤에 지웁니다.
+	var remove []string
+	for _, c := range plan.Uninstall {
+		remove = append(remove, c.Name)
+	}
+	if len(remove) > 0 {
+		if err := runPipTo(out, append([]string{"uninstall", "-y"}, remove...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPip 은 venv 의 pip 을 실행합니다. offline 이면 네트워크를 막습니다.
+func runPip(args ...string) error {
+	return runPipTo(os.Stdout, args...)
+}
+
+// runPipTo 는 pip 의 stdout 을 out 으로 보내는 runPip 입니다.
+func runPipTo(out io.Writer, args ...string) error {
+	cmd := exec.Command(pipPath(), args...)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if offlineMode() {
+		cmd.Env = offlineEnv()
+	}
+	return runProcess(context.Background(), cmd)
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [./...]",
+	Short: "Make .venv exactly match pigo.lock",
+	Long: `Compares the packages installed in .venv with pigo.lock, installs missing
+packages, reinstalls packages whose version or commit differs and uninstalls
+packages that are not locked (pip, setuptools and wheel are kept).
+
+With --dry-run the changes are only printed:
+  + name version          install
+  ~ name old -> new       reinstall
+  - name version          uninstall`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		var err error
+		if _, ok := cutWorkspacePattern(args); ok {
+			err = forEachMember(func(member string) error {
+				return runSync(syncOptions{DryRun: dryRun, Prune: true})
+			})
+		} else if len(args) > 0 {
+			log.Fatalf("error: unexpected argument %q", args[0])
+		} else {
+			err = runSync(syncOptions{DryRun: dryRun, Prune: true})
+		}
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolP("dry-run", "n", false, "Print the changes without applying them")
+}
@@ -0,0 +1,587 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/spf13/cobra"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [packages] [flags] [-- runner args]",
+	Short: "Run tests with pytest or unittest",
+	Long: `Runs the tests of each package (directory) with the .venv python, like go test.
+pytest is used if it is installed in .venv, otherwise unittest discovery.
+
+  pigo test                 the whole project
+  pigo test tests/api       one directory and its subdirectories
+  pigo test tests/...       each directory with tests under tests/, on its own
+  pigo test ./...           every pigo.work member, or each directory with tests
+
+Flags:
+  -run pattern   run only matching tests (pytest -k / unittest -k)
+  -v             stream the runner output (otherwise shown only on failure)
+  -count n       run each package n times
+  -json          print go test -json style events
+
+Unknown flags (use --flag=value) and arguments after -- are passed to pytest;
+they are an error when pytest is not installed.
+Packages expanded from dir/... run only the test files directly in that
+directory, so nested test directories are not run twice.
+A line is printed per package:
+  ok      tests/api   0.42s
+  FAIL    tests/db    1.03s
+  ?       scripts     [no test files]`,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts, patterns, err := parseTestFlags(args)
+		if err != nil {
+			fatal(err)
+		}
+		if opts.Help {
+			cmd.Help()
+			return
+		}
+		if err := runTests(patterns, opts); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+// testOptions 는 pigo test 옵션입니다.
+type testOptions struct {
+	Run        string   // 실행할 테스트 패턴 (-k)
+	Verbose    bool     // runner 출력을 그대로 보여줌
+	Count      int      // 패키지마다 실행할 횟수
+	JSON       bool     // go test -json 형식 출력
+	RunnerArgs []string // pytest 에 그대로 넘길 인자 (unittest 에서는 에러)
+	Help       bool
+}
+
+// parseTestFlags 는 pigo test 의 옵션을 읽고 패키지 인자를 돌려줍니다.
+func parseTestFlags(args []string) (testOptions, []string, error) {
+	opts := testOptions{Count: 1}
+	var patterns []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") {
+			patterns = append(patterns, arg)
+			continue
+		}
+		needValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("flag needs an argument: -%s", name)
+			}
+			i++
+			return args[i], nil
+		}
+		var err error
+		switch name {
+		case "-":
+			if arg == "--" {
+				opts.RunnerArgs = append(opts.RunnerArgs, args[i+1:]...)
+				return opts, patterns, nil
+			}
+			opts.RunnerArgs = append(opts.RunnerArgs, arg)
+		case "run", "-run":
+			opts.Run, err = needValue()
+		case "v", "-v":
+			opts.Verbose = true
+		case "json", "-json":
+			opts.JSON = true
+		case "count", "-count":
+			var v string
+			if v, err = needValue(); err == nil {
+				opts.Count, err = strconv.Atoi(v)
+				if err != nil || opts.Count < 1 {
+					err = fmt.Errorf("invalid -count=%s", v)
+				}
+			}
+		case "h", "help", "-help":
+			opts.Help = true
+		default:
+			opts.RunnerArgs = append(opts.RunnerArgs, arg)
+		}
+		if err != nil {
+			return opts, nil, err
+		}
+	}
+	return opts, patterns, nil
+}
+
+// testPackage 는 한 번에 실행하는 테스트 디렉토리입니다.
+type testPackage struct {
+	Name    string // 출력에 쓰는 이름 (현재 디렉토리 기준)
+	Dir     string
+	Project string // 이 패키지의 프로젝트 루트 (.venv 위치)
+	Flat    bool   // Dir 바로 아래의 테스트 파일만 실행 (dir/... 로 펼친 패키지)
+}
+
+// testPackages 는 인자를 테스트 패키지로 펼칩니다.
+// ./... 는 pigo.work 가 있으면 workspace 의 프로젝트들, 없으면 테스트 파일이 있는 모든 디렉토리입니다.
+// dir/... 는 dir 아래에서 테스트 파일이 있는 디렉토리이고, go test 처럼 각 디렉토리의 파일만 실행합니다.
+// 인자가 없으면 프로젝트 전체, 디렉토리를 적으면 그 아래 전체입니다.
+func testPackages(patterns []string) ([]testPackage, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		root := projectRoot()
+		return []testPackage{{Name: displayPathFrom(cwd, root), Dir: root, Project: root}}, nil
+	}
+	var pkgs []testPackage
+	for _, pattern := range patterns {
+		if pattern == workspacePattern {
+			if _, ok := findWorkspace(); ok {
+				members, err := workspaceMembers()
+				if err != nil {
+					return nil, err
+				}
+				for _, m := range members {
+					pkgs = append(pkgs, testPackage{Name: displayPathFrom(cwd, m), Dir: m, Project: m})
+				}
+				continue
+			}
+		}
+		if dir, ok := strings.CutSuffix(pattern, "/..."); ok {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range testDirs(abs) {
+				pkgs = append(pkgs, testPackage{Name: displayPathFrom(cwd, d), Dir: d, Project: projectRoot(), Flat: true})
+			}
+			continue
+		}
+		abs, err := filepath.Abs(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("%s: not a directory", pattern)
+		}
+		pkgs = append(pkgs, testPackage{Name: displayPathFrom(cwd, abs), Dir: abs, Project: projectRoot()})
+	}
+	return pkgs, nil
+}
+
+// testDirs 는 root 아래에서 테스트 파일(test_*.py, *_test.py)이 있는 디렉토리입니다.
+func testDirs(root string) []string {
+	rules := readIgnoreRules(projectRoot())
+	var dirs []string
+	seen := make(map[string]bool)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(projectRoot(), path)
+		if info.IsDir() {
+			if path != root && (skipDirs[info.Name()] || rules.match(rel, true)) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "pyvenv.cfg")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := info.Name()
+		if isTestFile(name) && !seen[filepath.Dir(path)] {
+			seen[filepath.Dir(path)] = true
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	return dirs
+}
+
+// ownTestFiles 는 dir 바로 아래의 테스트 파일입니다.
+func ownTestFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && isTestFile(e.Name()) {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	return files
+}
+
+func isTestFile(name string) bool {
+	return filepath.Ext(name) == ".py" && (strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test.py"))
+}
+
+// testEvent 는 go test -json 과 같은 형식의 출력 한 줄입니다.
+type testEvent struct {
+	Time    time.Time `json:",omitempty"`
+	Action  string
+	Package string   `json:",omitempty"`
+	Test    string   `json:",omitempty"`
+	Elapsed *float64 `json:",omitempty"`
+	Output  string   `json:",omitempty"`
+}
+
+// testResult 는 패키지를 한 번 실행한 결과입니다.
+type testResult struct {
+	Status  string // "ok", "FAIL", "?"
+	Elapsed time.Duration
+	Output  []byte
+	Tests   []testEvent // -json 일 때 테스트마다의 결과
+}
+
+// runTests 는 패키지마다 테스트를 실행하고 결과 줄을 출력합니다.
+// 실패한 패키지가 있으면 종료 코드 1 로 끝납니다.
+func runTests(patterns []string, opts testOptions) error {
+	pkgs, err := testPackages(patterns)
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defer chdirProject(cwd)
+
+	failed := false
+	for _, pkg := range pkgs {
+		if pkg.Project != projectRoot() {
+			if err := chdirProject(pkg.Project); err != nil {
+				return err
+			}
+		}
+		for n := 0; n < opts.Count; n++ {
+			res, err := runTestPackage(pkg, opts)
+			if err != nil {
+				return fmt.Errorf("%s: %w", pkg.Name, err)
+			}
+			if res.Status == "FAIL" {
+				failed = true
+			}
+			printTestResult(pkg, res, opts)
+		}
+		if err := chdirProject(cwd); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return &exitError{Code: 1}
+	}
+	return nil
+}
+
+// printTestResult 는 go test 처럼 패키지 결과 줄을 출력합니다.
+func printTestResult(pkg testPackage, res testResult, opts testOptions) {
+	if opts.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range res.Tests {
+			e.Package = pkg.Name
+			enc.Encode(testEvent{Time: time.Now(), Action: "run", Package: pkg.Name, Test: e.Test})
+			if e.Output != "" {
+				enc.Encode(testEvent{Time: time.Now(), Action: "output", Package: pkg.Name, Test: e.Test, Output: e.Output})
+			}
+			e.Time, e.Output = time.Now(), ""
+			enc.Encode(e)
+		}
+		for _, line := range strings.SplitAfter(string(res.Output), "\n") {
+			if line != "" {
+				enc.Encode(testEvent{Time: time.Now(), Action: "output", Package: pkg.Name, Output: line})
+			}
+		}
+		action := map[string]string{"ok": "pass", "FAIL": "fail", "?": "skip"}[res.Status]
+		elapsed := res.Elapsed.Seconds()
+		enc.Encode(testEvent{Time: time.Now(), Action: action, Package: pkg.Name, Elapsed: &elapsed})
+		return
+	}
+	if res.Status == "FAIL" && !opts.Verbose {
+		os.Stdout.Write(res.Output)
+	}
+	switch res.Status {
+	case "?":
+		fmt.Printf("?   \t%s\t[no test files]\n", pkg.Name)
+	default:
+		fmt.Printf("%-4s\t%s\t%.3fs\n", res.Status, pkg.Name, res.Elapsed.Seconds())
+	}
+}
+
+// runTestPackage 는 패키지 하나를 pytest 또는 unittest 로 실행합니다.
+// 두 runner 모두 테스트가 없으면 종료 코드 5 를 돌려줍니다.
+// pkg.Flat 이면 pytest 에는 테스트 파일을 직접 넘기고, unittest 는 하위 디렉토리의 테스트를 뺍니다.
+func runTestPackage(pkg testPackage, opts testOptions) (testResult, error) {
+	files := ownTestFiles(pkg.Dir)
+	if pkg.Flat && len(files) == 0 || !pkg.Flat && len(testDirs(pkg.Dir)) == 0 {
+		return testResult{Status: "?"}, nil
+	}
+	pytest := hasPytest()
+	if !pytest && len(opts.RunnerArgs) > 0 {
+		return testResult{}, fmt.Errorf("runner arguments %s need pytest, which is not installed in .venv", strings.Join(opts.RunnerArgs, " "))
+	}
+	report, err := os.CreateTemp("", "pigo-test-*")
+	if err != nil {
+		return testResult{}, err
+	}
+	report.Close()
+	defer os.Remove(report.Name())
+
+	var args []string
+	if pytest {
+		args = []string{"-m", "pytest"}
+		if pkg.Flat {
+			args = append(args, files...)
+		} else {
+			args = append(args, pkg.Dir)
+		}
+		if opts.Verbose {
+			args = append(args, "-v")
+		} else {
+			args = append(args, "-q")
+		}
+		if opts.Run != "" {
+			args = append(args, "-k", opts.Run)
+		}
+		if opts.JSON {
+			args = append(args, "--junitxml="+report.Name())
+		}
+	} else {
+		verbosity, reportPath, flat := "1", "", ""
+		if opts.Verbose {
+			verbosity = "2"
+		}
+		if opts.JSON {
+			reportPath = report.Name()
+		}
+		if pkg.Flat {
+			flat = "1"
+		}
+		args = []string{"-c", unittestRunnerScript, pkg.Dir, projectRoot(), opts.Run, verbosity, reportPath, flat}
+	}
+	args = append(args, opts.RunnerArgs...)
+
+	env, err := runEnvironment("", nil)
+	if err != nil {
+		return testResult{}, err
+	}
+	var out bytes.Buffer
+	c := exec.Command(pythonPath(), args...)
+	c.Dir = projectRoot()
+	c.Env = env
+	c.Stdin = os.Stdin
+	switch {
+	case opts.Verbose && !opts.JSON:
+		c.Stdout, c.Stderr = os.Stdout, os.Stderr
+	default:
+		c.Stdout, c.Stderr = &out, &out
+	}
+
+	start := time.Now()
+	err = runProcess(context.Background(), c)
+	res := testResult{Status: "ok", Elapsed: time.Since(start), Output: out.Bytes()}
+	var ee *exitError
+	if errors.As(err, &ee) {
+		res.Status = "FAIL"
+		if ee.Code == 5 {
+			res.Status = "?"
+		}
+	} else if err != nil {
+		return res, err
+	}
+
+	if opts.JSON {
+		if pytest {
+			res.Tests, err = readJUnitReport(report.Name())
+		} else {
+			res.Tests, err = readUnittestReport(report.Name())
+		}
+		if err != nil && res.Status != "?" {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// hasPytest 는 .venv 에 pytest 가 설치되어 있는지 확인합니다.
+func hasPytest() bool {
+	dists, err := dist.LoadVenv(venvDir())
+	if err != nil {
+		return false
+	}
+	_, ok := dist.Index(dists)["pytest"]
+	return ok
+}
+
+// readJUnitReport 는 pytest --junitxml 결과를 테스트 이벤트로 바꿉니다.
+func readJUnitReport(path string) ([]testEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	type message struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type testCase struct {
+		ClassName string   `xml:"classname,attr"`
+		Name      string   `xml:"name,attr"`
+		Time      float64  `xml:"time,attr"`
+		Failure   *message `xml:"failure"`
+		Error     *message `xml:"error"`
+		Skipped   *message `xml:"skipped"`
+	}
+	type suite struct {
+		Cases  []testCase `xml:"testcase"`
+		Suites []suite    `xml:"testsuite"`
+	}
+	var root suite
+	if err := xml.Unmarshal(data, &root); err != nil && err != io.EOF {
+		return nil, err
+	}
+	var events []testEvent
+	var walk func(s suite)
+	walk = func(s suite) {
+		for _, c := range s.Cases {
+			elapsed := c.Time
+			e := testEvent{Action: "pass", Test: c.Name, Elapsed: &elapsed}
+			if c.ClassName != "" {
+				e.Test = c.ClassName + "." + c.Name
+			}
+			switch {
+			case c.Failure != nil:
+				e.Action, e.Output = "fail", c.Failure.Text
+			case c.Error != nil:
+				e.Action, e.Output = "fail", c.Error.Text
+			case c.Skipped != nil:
+				e.Action, e.Output = "skip", c.Skipped.Message
+			}
+			events = append(events, e)
+		}
+		for _, sub := range s.Suites {
+			walk(sub)
+		}
+	}
+	walk(root)
+	return events, nil
+}
+
+// readUnittestReport 는 unittestRunnerScript 가 쓴 결과를 읽습니다.
+func readUnittestReport(path string) ([]testEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var events []testEvent
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	return events, json.Unmarshal(data, &events)
+}
+
+// unittestRunnerScript 는 unittest discovery 로 테스트를 실행하고, 테스트마다의 결과를
+// JSON 으로 남기는 python 코드입니다. 인자: start_dir top_dir pattern verbosity report_path flat
+// flat 이면 start_dir 바로 아래 파일의 테스트만 실행합니다.
+// 테스트가 하나도 없으면 pytest 처럼 5 로 끝납니다.
+const unittestRunnerScript = `import json, os, sys, time, unittest
+
+start_dir, top_dir, pattern, verbosity, report, flat = sys.argv[1:7]
+sys.argv = sys.argv[:1]
+sys.path.insert(0, top_dir)
+loader = unittest.TestLoader()
+if pattern:
+    loader.testNamePatterns = [pattern if "*" in pattern else "*%s*" % pattern]
+
+
+def discover(pattern):
+    try:
+        return loader.discover(start_dir, pattern=pattern, top_level_dir=top_dir)
+    except ImportError:
+        # __init__.py 가 없는 디렉토리는 그 디렉토리를 기준으로 찾습니다.
+        return loader.discover(start_dir, pattern=pattern, top_level_dir=start_dir)
+
+
+def in_start_dir(test):
+    if type(test).__name__ == "_FailedTest":
+        # import 에 실패한 모듈은 테스트 이름이 모듈 이름입니다.
+        module = test._testMethodName.rpartition(".")[2]
+        return os.path.isfile(os.path.join(start_dir, module + ".py"))
+    path = getattr(sys.modules.get(type(test).__module__), "__file__", None)
+    return path is not None and os.path.samefile(os.path.dirname(os.path.abspath(path)), start_dir)
+
+
+def own_tests(suite):
+    # 하위 디렉토리의 테스트는 그 디렉토리의 패키지로 따로 실행합니다.
+    kept = unittest.TestSuite()
+    for test in suite:
+        if isinstance(test, unittest.TestSuite):
+            kept.addTests(own_tests(test))
+        elif in_start_dir(test):
+            kept.addTest(test)
+    return kept
+
+suite = discover("test*.py")
+suite.addTests(discover("*_test.py"))
+if flat:
+    suite = own_tests(suite)
+events = []
+
+class Result(unittest.TextTestResult):
+    def startTest(self, test):
+        self._started = time.time()
+        super().startTest(test)
+
+    def _record(self, test, action, output=""):
+        elapsed = round(time.time() - getattr(self, "_started", time.time()), 3)
+        events.append({"Action": action, "Test": test.id(), "Elapsed": elapsed, "Output": output})
+
+    def addSuccess(self, test):
+        super().addSuccess(test)
+        self._record(test, "pass")
+
+    def addFailure(self, test, err):
+        super().addFailure(test, err)
+        self._record(test, "fail", self._exc_info_to_string(err, test))
+
+    def addError(self, test, err):
+        super().addError(test, err)
+        self._record(test, "fail", self._exc_info_to_string(err, test))
+
+    def addSkip(self, test, reason):
+        super().addSkip(test, reason)
+        self._record(test, "skip", reason)
+
+    def addExpectedFailure(self, test, err):
+        super().addExpectedFailure(test, err)
+        self._record(test, "pass")
+
+    def addUnexpectedSuccess(self, test):
+        super().addUnexpectedSuccess(test)
+        self._record(test, "fail", "unexpected success")
+
+result = unittest.TextTestRunner(verbosity=int(verbosity), resultclass=Result).run(suite)
+if report:
+    with open(report, "w") as f:
+        json.dump(events, f)
+if result.testsRun == 0:
+    sys.exit(5)
+sys.exit(0 if result.wasSuccessful() else 1)
+`
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+}
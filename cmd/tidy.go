@@ -2,16 +2,14 @@ package cmd
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
 	sitter "github.com/smacker/go-tree-sitter"
 	python "github.com/smacker/go-tree-sitter/python"
 	"github.com/spf13/cobra"
@@ -22,11 +20,17 @@ type ImportItem struct {
 	Type   string
 	Module string
 	Names  []string
+	Line   int
+}
+
+// ImportSite 는 import 문이 등장한 위치입니다.
+type ImportSite struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
 }
 
 type PkgMeta struct {
-	ImportNames []string `json:"imports"`
-	Requires    []string `json:"requires"`
+	ImportNames []string
 }
 
 // 코드에 import는 없지만 지워지면 안 되는 개발/배포 도구들
@@ -38,99 +42,6 @@ var defaultIgnoreList = map[string]bool{
 	"pre-commit": true, "poetry": true,
 }
 
-// --- [강력해진 Python 메타데이터 분석 스크립트] ---
-const pythonMapperScript = `
-import sys
-import json
-import importlib.metadata
-import os
-
-def parse_req_name(req_str):
-    if not req_str: return ""
-    name = req_str.split('(')[0].split(';')[0].split('<')[0].split('>')[0].split('=')[0]
-    return name.strip().lower()
-
-def get_import_names_from_files(dist):
-    """
-    top_level.txt가 없을 때, 실제 설치된 파일 경로를 분석하여 import 이름을 추출
-    예: python-jose -> site-packages/jose/__init__.py -> 'jose' 추출
-    """
-    modules = set()
-    if not dist.files:
-        return []
-
-    for path in dist.files:
-        # 경로는 보통 'jose/__init__.py' 또는 'six.py' 형태임
-        parts = str(path).split(os.sep)
-        
-        # 최상위 경로가 .dist-info나 .egg-info면 무시
-        if len(parts) > 0:
-            top = parts[0]
-            if top.endswith('.dist-info') or top.endswith('.egg-info') or top == '__pycache__':
-                continue
-            
-            # .py 파일인 경우 (예: six.py)
-            if top.endswith('.py'):
-                modules.add(top[:-3])
-            # 폴더인 경우 (예: jose/)
-            else:
-                modules.add(top)
-    
-    return list(modules)
-
-def get_package_info(package_names):
-    result = {}
-    for pkg_raw in package_names:
-        # 안전장치: pydantic[email] -> pydantic
-        pkg = pkg_raw.split('[')[0].strip()
-        
-        info = {"imports": [], "requires": []}
-        try:
-            dist = importlib.metadata.distribution(pkg)
-            
-            # 1. Imports 찾기 (top_level.txt 우선, 없으면 파일 분석)
-            if dist.read_text('top_level.txt'):
-                top_levels = dist.read_text('top_level.txt').split()
-                info["imports"] = [t.strip() for t in top_levels if t.strip()]
-            else:
-                # top_level.txt가 없으면 설치된 파일 리스트를 뒤진다 (여기가 핵심)
-                detected = get_import_names_from_files(dist)
-                if detected:
-                    info["imports"] = detected
-                else:
-                    # 최후의 수단: 이름 변환
-                    info["imports"] = [pkg.lower().replace('-', '_')]
-            
-            # 2. Dependencies 찾기 (pydantic -> email-validator 보호용)
-            requires = dist.requires
-            if requires:
-                deps = []
-                for req in requires:
-                    dep_name = parse_req_name(req)
-                    if dep_name:
-                        deps.append(dep_name)
-                info["requires"] = deps
-
-        except Exception:
-            # 패키지 미설치 시 Fallback
-            info["imports"] = [pkg.lower().replace('-', '_'), pkg]
-            
-        result[pkg_raw] = info 
-    return result
-
-if __name__ == "__main__":
-    input_data = sys.stdin.read()
-    if not input_data:
-        print("{}")
-        sys.exit(0)
-    try:
-        packages = json.loads(input_data)
-        result = get_package_info(packages)
-        print(json.dumps(result))
-    except Exception:
-        print("{}")
-`
-
 // --- [Tree-sitter 함수들 (변경 없음)] ---
 func extractImports(root *sitter.Node, src []byte) []ImportItem {
 	var res []ImportItem
@@ -142,7 +53,7 @@ func extractImports(root *sitter.Node, src []byte) []ImportItem {
 				child := n.NamedChild(i)
 				moduleName := resolveModuleName(child, src)
 				if moduleName != "" {
-					res = append(res, ImportItem{Type: "import", Module: moduleName})
+					res = append(res, ImportItem{Type: "import", Module: moduleName, Line: int(n.StartPoint().Row) + 1})
 				}
 			}
 		case "import_from_statement":
@@ -164,7 +75,7 @@ func extractImports(root *sitter.Node, src []byte) []ImportItem {
 				namesNode := n.ChildByFieldName("names")
 				names = getImportNames(namesNode, src)
 			}
-			res = append(res, ImportItem{Type: "from", Module: module, Names: names})
+			res = append(res, ImportItem{Type: "from", Module: module, Names: names, Line: int(n.StartPoint().Row) + 1})
 		}
 		for i := 0; i < int(n.ChildCount()); i++ {
 			walk(n.Child(i))
@@ -209,22 +120,22 @@ func isLocalModule(rootPath, moduleName string) bool {
 	return false
 }
 
-func parsePackageName(line string) string {
-	if idx := strings.Index(line, "#"); idx != -1 {
-		line = line[:idx]
-	}
-	line = strings.TrimSpace(line)
-	if line == "" {
-		return ""
+// readRequirements 는 requirements 파일에 선언된 requirement 들을 읽습니다.
+func readRequirements(path string) ([]dist.Requirement, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
-	re := regexp.MustCompile(`([<>=~;]+)`)
-	parts := re.Split(line, 2)
-	pkgName := strings.TrimSpace(parts[0])
+	defer f.Close()
 
-	if idx := strings.Index(pkgName, "["); idx != -1 {
-		pkgName = strings.TrimSpace(pkgName[:idx])
+	var reqs []dist.Requirement
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if req, ok := modfile.ParseRequirementLine(scanner.Text()); ok {
+			reqs = append(reqs, req)
+		}
 	}
-	return pkgName
+	return reqs, scanner.Err()
 }
 
 func getRootModule(moduleName string) string {
@@ -232,198 +143,247 @@ func getRootModule(moduleName string) string {
 	return parts[0]
 }
 
-func fetchPackageInfo(packageNames []string) (map[string]PkgMeta, error) {
-	inputJSON, err := json.Marshal(packageNames)
-	if err != nil {
-		return nil, err
-	}
-	cmd := exec.Command("python", "-c", pythonMapperScript)
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return nil, err
-	}
-	go func() {
-		defer stdin.Close()
-		io.WriteString(stdin, string(inputJSON))
-	}()
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("python script failed")
+// fetchPackageInfo 는 venv 에서 읽은 메타데이터로 (python 실행 없이)
+// 패키지별 import 이름을 구합니다.
+func fetchPackageInfo(installed map[string]*dist.Distribution, packageNames []string, replaced map[string]string) map[string]PkgMeta {
+	result := make(map[string]PkgMeta)
+	for _, pkg := range packageNames {
+		d, ok := installed[dist.NormalizeName(pkg)]
+		if dir, isReplaced := replaced[dist.NormalizeName(pkg)]; isReplaced && (!ok || d.Editable != "") {
+			// replace 된 패키지는 로컬 소스에서 import 이름을 찾습니다.
+			if names := dist.LocalImportNames(dir); len(names) > 0 {
+				result[pkg] = PkgMeta{ImportNames: names}
+				continue
+			}
+		}
+		if !ok {
+			// 패키지 미설치 시 Fallback
+			result[pkg] = PkgMeta{ImportNames: []string{strings.ReplaceAll(strings.ToLower(pkg), "-", "_"), pkg}}
+			continue
+		}
+		result[pkg] = PkgMeta{ImportNames: d.ImportNames}
 	}
-	var result map[string]PkgMeta
-	json.Unmarshal(output, &result)
-	return result, nil
+	return result
 }
 
-var tidyCmd = &cobra.Command{
-	Use:   "tidy [path]",
-	Short: "Automatically remove unused packages",
-	Long:  `Analyzes dependencies by inspecting installed package files to accurately map PyPI names to import names without hardcoded lists.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		searchPath := "."
-		if len(args) > 0 {
-			searchPath = args[0]
+// isPackageUsed 는 패키지가 코드에서 직접 import 되는지 확인합니다.
+func isPackageUsed(pkgName string, meta PkgMeta, importedSet map[string]bool) bool {
+	// 1. 메타데이터 매핑 확인
+	for _, importName := range meta.ImportNames {
+		if importedSet[importName] || importedSet[getRootModule(importName)] {
+			return true
 		}
-		absSearchPath, _ := filepath.Abs(searchPath)
-		reqPath := filepath.Join(searchPath, "requirements.txt")
+	}
 
-		if _, err := os.Stat(reqPath); os.IsNotExist(err) {
-			log.Fatalf("requirements.txt not found")
+	// 2. 단순 이름 일치 (Fallback)
+	for imp := range importedSet {
+		if strings.EqualFold(imp, pkgName) {
+			return true
 		}
+	}
+	return false
+}
 
-		fmt.Println("Reading requirements.txt...")
-		reqFile, err := os.Open(reqPath)
+// skipDirs 는 import 탐색에서 제외할 디렉토리입니다. (가상환경, VCS, 캐시)
+var skipDirs = map[string]bool{
+	".venv": true, "venv": true, ".git": true, ".hg": true, "__pycache__": true,
+	"node_modules": true, ".tox": true, ".mypy_cache": true, ".pytest_cache": true,
+}
+
+// collectPythonFiles 는 searchPath 아래의 .py 파일을 찾습니다.
+// 가상환경(pyvenv.cfg 가 있는 디렉토리), skipDirs, exclude 에 있는 절대경로는 건너뜁니다.
+func collectPythonFiles(searchPath string, exclude map[string]bool) ([]string, error) {
+	var files []string
+	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			log.Fatal(err)
+			return nil
 		}
-
-		var originalLines []string
-		var reqPackages []string
-		scanner := bufio.NewScanner(reqFile)
-		for scanner.Scan() {
-			line := scanner.Text()
-			originalLines = append(originalLines, line)
-			pkgName := parsePackageName(line)
-			if pkgName != "" {
-				reqPackages = append(reqPackages, pkgName)
+		if info.IsDir() {
+			if path != searchPath && skipDirs[info.Name()] {
+				return filepath.SkipDir
 			}
-		}
-		reqFile.Close()
-
-		fmt.Println("Analyzing python environment (Smart Mode)...")
-		pkgInfoMap, _ := fetchPackageInfo(reqPackages)
-
-		fmt.Println("Scanning code imports...")
-		importedSet := make(map[string]bool)
-
-		files := []string{}
-		filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-			if err == nil && !info.IsDir() && filepath.Ext(path) == ".py" {
-				files = append(files, path)
+			if abs, err := filepath.Abs(path); err == nil && exclude[abs] {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "pyvenv.cfg")); err == nil {
+				return filepath.SkipDir
 			}
 			return nil
-		})
-
-		parser := sitter.NewParser()
-		parser.SetLanguage(python.GetLanguage())
-
-		for _, filename := range files {
-			func() {
-				f, err := os.Open(filename)
-				if err != nil {
-					return
-				}
-				defer f.Close()
-				src, _ := io.ReadAll(f)
-				tree := parser.Parse(nil, src)
-				imports := extractImports(tree.RootNode(), src)
-				for _, imp := range imports {
-					if !isLocalModule(absSearchPath, imp.Module) {
-						importedSet[getRootModule(imp.Module)] = true
-						importedSet[imp.Module] = true
-					}
-				}
-			}()
 		}
+		if filepath.Ext(path) == ".py" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
 
-		// 의존성 보호 목록 생성
-		protectedDeps := make(map[string]bool)
-		for _, meta := range pkgInfoMap {
-			isDirectlyUsed := false
+// displayPath 는 출력용으로 현재 디렉토리 기준 상대경로를 돌려줍니다.
+func displayPath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, abs); err == nil {
+		return rel
+	}
+	return path
+}
 
-			// 메타데이터(설치된 파일 분석 결과)로 확인
-			for _, importName := range meta.ImportNames {
-				if importedSet[importName] || importedSet[getRootModule(importName)] {
-					isDirectlyUsed = true
-					break
-				}
-			}
+// scanImports 는 searchPath 아래 .py 파일에서 로컬 모듈이 아닌 import 를 모읍니다.
+// 결과는 모듈 이름 -> import 위치 목록입니다.
+func scanImports(searchPath string) (map[string][]ImportSite, error) {
+	absSearchPath, err := filepath.Abs(searchPath)
+	if err != nil {
+		return nil, err
+	}
+	// 프로젝트 안에 있는 replace 대상 패키지의 import 는 그 패키지가 선언한
+	// 의존성이므로 프로젝트 import 로 세지 않습니다.
+	replaced, err := replacements(searchPath)
+	if err != nil {
+		return nil, err
+	}
+	exclude := make(map[string]bool)
+	for _, dir := range replaced {
+		exclude[dir] = true
+	}
+	files, err := collectPythonFiles(searchPath, exclude)
+	if err != nil {
+		return nil, err
+	}
 
-			if isDirectlyUsed {
-				for _, dep := range meta.Requires {
-					protectedDeps[strings.ToLower(dep)] = true
-				}
+	parser := sitter.NewParser()
+	parser.SetLanguage(python.GetLanguage())
+
+	sites := make(map[string][]ImportSite)
+	for _, filename := range files {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+		tree := parser.Parse(nil, src)
+		for _, imp := range extractImports(tree.RootNode(), src) {
+			if !isLocalModule(absSearchPath, imp.Module) {
+				sites[imp.Module] = append(sites[imp.Module], ImportSite{File: displayPath(filename), Line: imp.Line})
 			}
 		}
+		tree.Close()
+	}
+	return sites, nil
+}
 
-		fmt.Println("Cleaning up...")
-		var newLines []string
-		var removedCount int
+// runTidy 는 searchPath 프로젝트의 requirements.txt 에서 쓰지 않는 의존성을 지웁니다.
+func runTidy(searchPath string) error {
+	reqPath := filepath.Join(searchPath, "requirements.txt")
 
-		for _, line := range originalLines {
-			pkgName := parsePackageName(line)
-			pkgLower := strings.ToLower(pkgName)
+	if _, err := os.Stat(reqPath); os.IsNotExist(err) {
+		return fmt.Errorf("requirements.txt not found in %s", searchPath)
+	}
 
-			if pkgName == "" || defaultIgnoreList[pkgLower] {
-				newLines = append(newLines, line)
-				continue
-			}
+	fmt.Println("Reading requirements.txt...")
+	reqFile, err := modfile.ReadRequirements(reqPath)
+	if err != nil {
+		return err
+	}
 
-			isUsed := false
+	var reqPackages []string
+	for _, req := range reqFile.List() {
+		reqPackages = append(reqPackages, req.Name)
+	}
 
-			// 1. 메타데이터 매핑 확인
-			if meta, ok := pkgInfoMap[line]; ok { // 키값 주의
-				for _, importName := range meta.ImportNames {
-					if importedSet[importName] || importedSet[getRootModule(importName)] {
-						isUsed = true
-						break
-					}
-				}
-			}
-			// line 키로 못 찾으면 pkgName 키로 재시도
-			if !isUsed {
-				if meta, ok := pkgInfoMap[pkgName]; ok {
-					for _, importName := range meta.ImportNames {
-						if importedSet[importName] || importedSet[getRootModule(importName)] {
-							isUsed = true
-							break
-						}
-					}
-				}
-			}
+	fmt.Println("Analyzing python environment (Smart Mode)...")
+	venvPath := filepath.Join(searchPath, ".venv")
+	dists, err := dist.LoadVenv(venvPath)
+	if err != nil {
+		return fmt.Errorf("reading installed packages: %w", err)
+	}
+	env, err := dist.VenvEnvironment(venvPath)
+	if err != nil {
+		return fmt.Errorf("reading python environment: %w", err)
+	}
+	installed := dist.Index(dists)
+	replaced, err := replacements(searchPath)
+	if err != nil {
+		return err
+	}
+	pkgInfoMap := fetchPackageInfo(installed, reqPackages, replaced)
 
-			// 2. 단순 이름 일치 (Fallback)
-			if !isUsed {
-				if importedSet[pkgName] {
-					isUsed = true
-				}
-				if !isUsed {
-					for imp := range importedSet {
-						if strings.EqualFold(imp, pkgName) {
-							isUsed = true
-							break
-						}
-					}
-				}
-			}
+	fmt.Println("Scanning code imports...")
+	importSites, err := scanImports(searchPath)
+	if err != nil {
+		return fmt.Errorf("scanning imports: %w", err)
+	}
+	importedSet := make(map[string]bool)
+	for module := range importSites {
+		importedSet[getRootModule(module)] = true
+		importedSet[module] = true
+	}
 
-			// 3. 의존성 보호 (pydantic -> email-validator 등)
-			if !isUsed && protectedDeps[pkgLower] {
-				isUsed = true
-			}
+	// 남길 requirement 를 먼저 고르고, 그 requirement 들이 (활성화된 extras 와
+	// 현재 interpreter 기준으로) 전이적으로 필요로 하는 패키지를 보호합니다.
+	// 안 쓰는 extra 로만 도달하는 패키지는 보호하지 않습니다.
+	// 남길 requirement 자체는 marker 가 현재 interpreter 에서 거짓이어도 남기므로 marker 없이 넣습니다.
+	var roots []dist.Requirement
+	for _, req := range reqFile.List() {
+		keep := defaultIgnoreList[dist.NormalizeName(req.Name)] || isPackageUsed(req.Name, pkgInfoMap[req.Name], importedSet)
+		if !keep {
+			// 다른 플랫폼/버전용 requirement 는 여기서 사용 여부를 알 수 없으므로 남깁니다.
+			match, err := dist.EvaluateMarker(req.Marker, env, nil)
+			keep = err != nil || !match
+		}
+		if keep {
+			roots = append(roots, dist.Requirement{Name: req.Name, Extras: req.Extras})
+		}
+	}
+	protectedDeps := dist.Closure(installed, roots, env)
+
+	fmt.Println("Cleaning up...")
+	var removedCount int
+	for _, req := range reqFile.List() {
+		if _, isUsed := protectedDeps[dist.NormalizeName(req.Name)]; !isUsed {
+			fmt.Printf("Removing: %s\n", req.Name)
+			reqFile.Remove(req.Name)
+			removedCount++
+		}
+	}
 
-			if isUsed {
-				newLines = append(newLines, line)
-			} else {
-				fmt.Printf("Removing: %s\n", pkgName)
-				removedCount++
-			}
+	if removedCount > 0 {
+		if err := reqFile.Write(reqPath); err != nil {
+			return err
 		}
+		fmt.Printf("\nRemoved %d packages.\n", removedCount)
+	} else {
+		fmt.Println("\nClean.")
+	}
+	return nil
+}
 
-		if removedCount > 0 {
-			outFile, err := os.Create(reqPath)
-			if err != nil {
-				log.Fatal(err)
-			}
-			defer outFile.Close()
-			w := bufio.NewWriter(outFile)
-			for _, l := range newLines {
-				fmt.Fprintln(w, l)
-			}
-			w.Flush()
-			fmt.Printf("\nRemoved %d packages.\n", removedCount)
+var tidyCmd = &cobra.Command{
+	Use:   "tidy [path]",
+	Short: "Automatically remove unused packages",
+	Long: `Analyzes dependencies by inspecting installed package files to accurately map PyPI names to import names without hardcoded lists.
+Use ./... to tidy every project listed in pigo.work.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		searchPath := projectRoot()
+		if len(args) > 0 {
+			searchPath = args[0]
+		}
+
+		var err error
+		if searchPath == workspacePattern {
+			err = forEachMember(func(member string) error {
+				return runTidy(member)
+			})
 		} else {
-			fmt.Println("\nClean.")
+			err = runTidy(searchPath)
+		}
+		if err != nil {
+			log.Fatalf("error: %v", err)
 		}
 	},
 }
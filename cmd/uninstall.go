@@ -9,102 +9,212 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
-	_const "github.com/janghanul090801/pigo/cmd/const"
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
 	"github.com/spf13/cobra"
 )
 
 // uninstallCmd represents the uninstall command
 var uninstallCmd = &cobra.Command{
-	Use:                "uninstall",
-	Short:              "Uninstall package and remove from requirements.txt",
-	Long:               `Uninstall a package using pip and remove it from the requirements.txt file.`,
+	Use:   "uninstall",
+	Short: "Uninstall package and remove from requirements.txt",
+	Long: `Uninstall a package using pip and remove it from the requirements.txt file.
+
+Dependencies of the removed packages that are no longer needed by any remaining
+requirement are removed too (--prune, default). They are listed for confirmation
+first; -y skips the confirmation and --no-prune keeps them.`,
 	DisableFlagParsing: true,
 	Run: func(cmd *cobra.Command, args []string) {
+		opts, args, err := parseUninstallFlags(args)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+
 		uninstallArgs := append([]string{"uninstall"}, args...)
-		uninstallCmd := exec.Command(_const.PIPPATHWINDOW, uninstallArgs...)
+		if opts.Prune {
+			orphans, err := findOrphans(args)
+			if err != nil {
+				log.Fatalf("error: %v", err)
+			}
+			if len(orphans) > 0 {
+				if !opts.Yes && !confirmUninstall(args, orphans) {
+					fmt.Println("Aborted.")
+					return
+				}
+				for _, d := range orphans {
+					uninstallArgs = append(uninstallArgs, d.Name)
+				}
+				opts.Yes = true
+			}
+		}
+		if opts.Yes {
+			uninstallArgs = append(uninstallArgs, "-y")
+		}
+		uninstallCmd := exec.Command(pipPath(), uninstallArgs...)
 		uninstallCmd.Stdout = os.Stdout
 		uninstallCmd.Stderr = os.Stderr
 		uninstallCmd.Stdin = os.Stdin
 
-		if err := uninstallCmd.Run(); err != nil {
-			log.Fatalf("error executing pip uninstall: %v", err)
+		ctx, cancel := timeoutContext(opts.Timeout)
+		defer cancel()
+		if err := runProcess(ctx, uninstallCmd); err != nil {
+			fatal(fmt.Errorf("executing pip uninstall: %w", err))
 		}
 
-		targetPackages := make(map[string]bool)
+		reqPath := projectPath("requirements.txt")
+		reqFile, err := modfile.ReadRequirements(reqPath)
+		if err != nil {
+			log.Fatalf("error reading requirements.txt: %v", err)
+		}
+		removed := false
 		for _, arg := range args {
-			if !strings.HasPrefix(arg, "-") {
-				targetPackages[strings.ToLower(arg)] = true
+			req, ok := modfile.ParseRequirementLine(arg)
+			if !ok || strings.HasPrefix(arg, "-") {
+				continue
+			}
+			if reqFile.Remove(req.Name) {
+				fmt.Printf("Removing %s from requirements.txt\n", dist.NormalizeName(req.Name))
+				removed = true
 			}
 		}
-
-		if len(targetPackages) == 0 {
-			return
+		if removed {
+			if err := reqFile.Write(reqPath); err != nil {
+				log.Fatalf("error writing requirements.txt: %v", err)
+			}
 		}
 
-		file, err := os.Open("requirements.txt")
-		if os.IsNotExist(err) {
-			return
-		} else if err != nil {
-			log.Fatalf("error opening requirements.txt: %v", err)
+		if _, err := os.Stat(lockPath()); err == nil {
+			if err := writeLock(); err != nil {
+				log.Printf("warning: updating %s: %v", modfile.LockFileName, err)
+			}
 		}
-		defer file.Close()
-
-		var newLines []string
-		scanner := bufio.NewScanner(file)
+	},
+}
 
-		for scanner.Scan() {
-			line := scanner.Text()
-			trimmedLine := strings.TrimSpace(line)
+// uninstallOptions 는 pip 에 넘기지 않고 pigo 가 직접 처리하는 uninstall 옵션입니다.
+type uninstallOptions struct {
+	Prune   bool // 더 이상 필요 없는 의존성도 삭제
+	Yes     bool // 확인 없이 삭제
+	Timeout time.Duration
+}
 
-			if trimmedLine == "" {
-				continue
+// parseUninstallFlags 는 args 에서 pigo 의 uninstall 옵션을 꺼내고 나머지를 돌려줍니다.
+func parseUninstallFlags(args []string) (uninstallOptions, []string, error) {
+	opts := uninstallOptions{Prune: true}
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if d, next, ok, err := cutTimeoutFlag(args, i); ok {
+			if err != nil {
+				return opts, nil, err
 			}
-
-			if strings.HasPrefix(trimmedLine, "#") {
-				newLines = append(newLines, line)
-				continue
+			opts.Timeout, i = d, next
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--prune":
+			opts.Prune = true
+			if hasValue {
+				on, err := strconv.ParseBool(value)
+				if err != nil {
+					return opts, nil, fmt.Errorf("invalid --prune=%s", value)
+				}
+				opts.Prune = on
 			}
+		case "--no-prune":
+			opts.Prune = false
+		case "-y", "--yes":
+			opts.Yes = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return opts, rest, nil
+}
 
-			delimiters := "=<>!~@;["
-
-			idx := strings.IndexAny(trimmedLine, delimiters)
-
-			var pkgNameInFile string
-			if idx == -1 {
-				pkgNameInFile = trimmedLine
+// findOrphans 는 args 의 패키지를 지우면 남은 requirement 어디에서도 필요하지 않게 되는
+// 의존성을 찾습니다. requirements.txt 에 적힌 패키지와 pip, setuptools, wheel 은 남깁니다.
+func findOrphans(args []string) ([]*dist.Distribution, error) {
+	removing := make(map[string]bool)
+	var removed []dist.Requirement
+	for _, arg := range args {
+		if req, ok := modfile.ParseRequirementLine(arg); ok && !strings.HasPrefix(arg, "-") {
+			removing[dist.NormalizeName(req.Name)] = true
+			removed = append(removed, req)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	venvPath := venvDir()
+	dists, err := dist.LoadVenv(venvPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading installed packages: %w", err)
+	}
+	env, err := dist.VenvEnvironment(venvPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading python environment: %w", err)
+	}
+	var remaining []dist.Requirement
+	if _, err := os.Stat(projectPath("requirements.txt")); err == nil {
+		reqs, err := readRequirements(projectPath("requirements.txt"))
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			if removing[dist.NormalizeName(req.Name)] {
+				// requirements.txt 에 적힌 extras 까지 따라가도록 그 줄을 씁니다.
+				removed = append(removed, req)
 			} else {
-				pkgNameInFile = trimmedLine[:idx]
+				remaining = append(remaining, req)
 			}
-
-			pkgNameInFile = strings.ToLower(strings.TrimSpace(pkgNameInFile))
-
-			if _, found := targetPackages[pkgNameInFile]; found {
-				fmt.Printf("Removing %s from requirements.txt\n", pkgNameInFile)
-				continue
-			}
-
-			newLines = append(newLines, line)
 		}
-
-		if err := scanner.Err(); err != nil {
-			log.Fatalf("error reading requirements.txt: %v", err)
+	}
+
+	installed := dist.Index(dists)
+	needed := dist.Closure(installed, remaining, env)
+	var orphans []*dist.Distribution
+	for name := range dist.Closure(installed, removed, env) {
+		d, ok := installed[name]
+		if !ok || removing[name] || syncProtected[name] {
+			continue
 		}
-		file.Close()
-
-		outFile, err := os.Create("requirements.txt")
-		if err != nil {
-			log.Fatalf("error writing requirements.txt: %v", err)
+		if _, ok := needed[name]; !ok {
+			orphans = append(orphans, d)
 		}
-		defer outFile.Close()
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return dist.NormalizeName(orphans[i].Name) < dist.NormalizeName(orphans[j].Name)
+	})
+	return orphans, nil
+}
 
-		w := bufio.NewWriter(outFile)
-		for _, line := range newLines {
-			fmt.Fprintln(w, line)
+// confirmUninstall 은 지울 패키지와 함께 지워질 의존성을 보여주고 확인을 받습니다. 기본값은 no 입니다.
+func confirmUninstall(args []string, orphans []*dist.Distribution) bool {
+	fmt.Println("Uninstalling:")
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			fmt.Printf("  %s\n", arg)
 		}
-		w.Flush()
-	},
+	}
+	fmt.Println("Removing dependencies that are no longer required:")
+	for _, d := range orphans {
+		fmt.Printf("  %s %s\n", d.Name, d.Version)
+	}
+	fmt.Print("Proceed (y/N)? ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
 
 func init() {
@@ -0,0 +1,271 @@
+package vcs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ref 는 pip 의 VCS URL 하나입니다.
+//
+//	git+https://github.com/user/repo.git@main#egg=repo&subdirectory=pkg
+type Ref struct {
+	Repo     string // https://github.com/user/repo.git (git+ 제외)
+	Rev      string // main, v1.2.0, 커밋 해시 (없으면 기본 브랜치)
+	Fragment string // egg=repo&subdirectory=pkg
+}
+
+// Commit 은 Ref 를 해석한 결과입니다.
+type Commit struct {
+	Hash string
+	Time time.Time
+}
+
+// IsURL 은 s 가 pip 이 받는 VCS URL(git+...) 인지 확인합니다.
+func IsURL(s string) bool {
+	return strings.HasPrefix(s, "git+")
+}
+
+// ParseURL 은 git+<repo>[@rev][#fragment] 를 나눕니다.
+func ParseURL(s string) (Ref, error) {
+	rest, ok := strings.CutPrefix(s, "git+")
+	if !ok {
+		return Ref{}, fmt.Errorf("%s: only git+ URLs are supported", s)
+	}
+	var ref Ref
+	rest, ref.Fragment, _ = strings.Cut(rest, "#")
+	// user@host 의 @ 와 구분하기 위해 경로 부분의 마지막 @ 만 rev 로 봅니다.
+	pathStart := 0
+	if i := strings.Index(rest, "://"); i >= 0 {
+		pathStart = i + 3
+		if slash := strings.Index(rest[pathStart:], "/"); slash >= 0 {
+			pathStart += slash
+		}
+	}
+	if at := strings.LastIndex(rest[pathStart:], "@"); at >= 0 {
+		ref.Repo, ref.Rev = rest[:pathStart+at], rest[pathStart+at+1:]
+	} else {
+		ref.Repo = rest
+	}
+	if ref.Repo == "" {
+		return Ref{}, fmt.Errorf("%s: missing repository", s)
+	}
+	// git 이 옵션으로 읽지 않도록 - 로 시작하는 저장소와 rev 는 받지 않습니다.
+	if strings.HasPrefix(ref.Repo, "-") || strings.HasPrefix(ref.Rev, "-") {
+		return Ref{}, fmt.Errorf("%s: repository and revision must not start with '-'", s)
+	}
+	// pip 은 file://localhost/ 로 받은 저장소도 direct_url.json 에 그대로 적으므로 같은 형태로 맞춥니다.
+	if path, ok := strings.CutPrefix(ref.Repo, "file://localhost/"); ok {
+		ref.Repo = "file:///" + path
+	}
+	return ref, nil
+}
+
+// String 은 pip 에 넘길 수 있는 git+ URL 로 되돌립니다.
+func (r Ref) String() string {
+	s := "git+" + r.Repo
+	if r.Rev != "" {
+		s += "@" + r.Rev
+	}
+	if r.Fragment != "" {
+		s += "#" + r.Fragment
+	}
+	return s
+}
+
+// RequirementURL 은 PEP 508 의 "name @ url" 에 쓸 URL 입니다.
+// 오래된 pip 은 host 가 없는 file:/// URL 을 거부하므로 file://localhost/ 로 씁니다.
+func (r Ref) RequirementURL() string {
+	if path, ok := strings.CutPrefix(r.Repo, "file:///"); ok {
+		r.Repo = "file://localhost/" + path
+	}
+	return r.String()
+}
+
+// Egg 는 #egg= 에 적힌 패키지 이름입니다.
+func (r Ref) Egg() string {
+	for _, part := range strings.Split(r.Fragment, "&") {
+		if name, ok := strings.CutPrefix(part, "egg="); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+var pseudoVersionRe = regexp.MustCompile(`^0\.0\.0-(\d{14})-([0-9a-f]{12})$`)
+
+// PseudoVersion 은 Go 의 v0.0.0-yyyymmddhhmmss-abcdefabcdef 와 같은 형식의
+// 버전을 만듭니다. (커밋 시간은 UTC)
+func PseudoVersion(c Commit) string {
+	hash := c.Hash
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	return fmt.Sprintf("0.0.0-%s-%s", c.Time.UTC().Format("20060102150405"), hash)
+}
+
+// ParsePseudoVersion 은 pseudo-version 에서 커밋 해시 앞부분을 꺼냅니다.
+func ParsePseudoVersion(v string) (string, bool) {
+	m := pseudoVersionRe.FindStringSubmatch(v)
+	if m == nil {
+		return "", false
+	}
+	return m[2], true
+}
+
+var commitHashRe = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// CommitPrefix 는 rev 가 (짧은) 커밋 해시나 pseudo-version 이면 해시 부분을 돌려줍니다.
+// 브랜치나 태그 이름이면 false 입니다.
+func CommitPrefix(rev string) (string, bool) {
+	if hash, ok := ParsePseudoVersion(rev); ok {
+		return hash, true
+	}
+	if commitHashRe.MatchString(rev) {
+		return rev, true
+	}
+	return "", false
+}
+
+// Resolve 는 ref 의 rev 를 커밋 해시로 바꿉니다.
+// 저장소는 cacheDir/vcs 아래에 bare mirror 로 받아 두고, 이후에는 fetch 만 합니다.
+// rev 는 브랜치, 태그, (짧은) 커밋 해시, pseudo-version 을 쓸 수 있습니다.
+func Resolve(cacheDir string, ref Ref) (Commit, error) {
+	mirror, err := mirrorDir(cacheDir, ref.Repo)
+	if err != nil {
+		return Commit{}, err
+	}
+	rev := revision(ref)
+
+	// 이미 받아 둔 커밋이면 네트워크 없이 해석합니다. 브랜치와 태그는 항상 fetch 합니다.
+	commit, err := revParse(mirror, rev)
+	if err != nil || !strings.HasPrefix(commit.Hash, rev) {
+		if err := fetch(mirror, ref.Repo); err != nil {
+			return Commit{}, err
+		}
+		if commit, err = revParse(mirror, rev); err != nil {
+			return Commit{}, fmt.Errorf("%s: unknown revision %s", ref.Repo, ref.Rev)
+		}
+	}
+	return commit, nil
+}
+
+// ResolveCached 는 네트워크 없이 이미 받아 둔 mirror 에서만 rev 를 해석합니다.
+// 브랜치와 태그는 마지막으로 fetch 했을 때의 커밋이 됩니다.
+func ResolveCached(cacheDir string, ref Ref) (Commit, error) {
+	mirror := MirrorPath(cacheDir, ref.Repo)
+	if _, err := os.Stat(filepath.Join(mirror, "HEAD")); err != nil {
+		return Commit{}, fmt.Errorf("%s is not in the local cache", ref.Repo)
+	}
+	commit, err := revParse(mirror, revision(ref))
+	if err != nil {
+		return Commit{}, fmt.Errorf("%s: revision %s is not in the local cache", ref.Repo, ref.Rev)
+	}
+	return commit, nil
+}
+
+func revision(ref Ref) string {
+	if hash, ok := CommitPrefix(ref.Rev); ok {
+		return hash
+	}
+	if ref.Rev == "" {
+		return "HEAD"
+	}
+	return ref.Rev
+}
+
+// MirrorPath 는 repo 의 bare mirror 가 있을(또는 있는) 경로입니다.
+func MirrorPath(cacheDir, repo string) string {
+	sum := sha256.Sum256([]byte(repo))
+	return filepath.Join(cacheDir, "vcs", hex.EncodeToString(sum[:8]))
+}
+
+// Origin 은 repo 가 cacheDir 의 mirror 를 가리키는 file:// URL 이면
+// mirror 를 받아 온 원래 저장소 URL 을 돌려줍니다.
+func Origin(cacheDir, repo string) (string, bool) {
+	path, ok := strings.CutPrefix(repo, "file://")
+	if !ok {
+		return "", false
+	}
+	path = strings.TrimPrefix(path, "localhost")
+	rel, err := filepath.Rel(filepath.Join(cacheDir, "vcs"), filepath.FromSlash(path))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	out, err := git(filepath.FromSlash(path), "config", "--get", "remote.origin.url")
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(out), true
+}
+
+// mirrorDir 은 repo 의 bare mirror 경로입니다. 처음이면 clone 합니다.
+func mirrorDir(cacheDir, repo string) (string, error) {
+	dir := MirrorPath(cacheDir, repo)
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); err == nil {
+		return dir, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return "", err
+	}
+	tmp := dir + ".tmp"
+	os.RemoveAll(tmp)
+	if _, err := git("", "clone", "--mirror", "--quiet", "--", repo, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		return "", err
+	}
+	return dir, nil
+}
+
+func fetch(mirror, repo string) error {
+	_, err := git(mirror, "fetch", "--quiet", "--prune", "--tags", "--", repo, "+refs/heads/*:refs/heads/*")
+	return err
+}
+
+func revParse(mirror, rev string) (Commit, error) {
+	if strings.HasPrefix(rev, "-") {
+		return Commit{}, fmt.Errorf("invalid revision %q", rev)
+	}
+	out, err := git(mirror, "log", "-1", "--format=%H %ct", rev+"^{commit}", "--")
+	if err != nil {
+		return Commit{}, err
+	}
+	hash, ts, ok := strings.Cut(strings.TrimSpace(out), " ")
+	if !ok {
+		return Commit{}, fmt.Errorf("unexpected git output %q", out)
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return Commit{}, err
+	}
+	return Commit{Hash: hash, Time: time.Unix(sec, 0).UTC()}, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,156 @@
+package vcs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testRepo 는 t.TempDir() 안의 bare 저장소와 거기에 push 하는 작업 디렉토리입니다.
+type testRepo struct {
+	t    *testing.T
+	bare string
+	work string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	r := &testRepo{t: t, bare: filepath.Join(dir, "repo.git"), work: filepath.Join(dir, "work")}
+	r.run("", "init", "--quiet", "--bare", "--initial-branch=main", r.bare)
+	r.run("", "clone", "--quiet", r.bare, r.work)
+	r.run(r.work, "checkout", "--quiet", "-b", "main")
+	return r
+}
+
+func (r *testRepo) run(dir string, args ...string) string {
+	r.t.Helper()
+	out, err := git(dir, args...)
+	if err != nil {
+		r.t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(out)
+}
+
+// commit 은 when 시각의 커밋을 만들어 push 하고 해시를 돌려줍니다.
+func (r *testRepo) commit(msg string, when time.Time) string {
+	r.t.Helper()
+	if err := os.WriteFile(filepath.Join(r.work, "file.txt"), []byte(msg), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+	date := when.Format(time.RFC3339)
+	r.t.Setenv("GIT_AUTHOR_DATE", date)
+	r.t.Setenv("GIT_COMMITTER_DATE", date)
+	r.t.Setenv("GIT_AUTHOR_NAME", "test")
+	r.t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	r.t.Setenv("GIT_COMMITTER_NAME", "test")
+	r.t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	r.run(r.work, "add", "file.txt")
+	r.run(r.work, "commit", "--quiet", "-m", msg)
+	r.run(r.work, "push", "--quiet", "origin", "main")
+	return r.run(r.work, "rev-parse", "HEAD")
+}
+
+func (r *testRepo) url() string {
+	return "file://" + filepath.ToSlash(r.bare)
+}
+
+func TestResolvePinsCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	cache := t.TempDir()
+	first := repo.commit("first", time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC))
+	repo.run(repo.work, "tag", "v1.0.0")
+	repo.run(repo.work, "push", "--quiet", "origin", "v1.0.0")
+
+	ref, err := ParseURL("git+" + repo.url() + "@main#egg=demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Resolve(cache, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Hash != first {
+		t.Fatalf("Resolve(main) = %s, want %s", c.Hash, first)
+	}
+	pseudo := PseudoVersion(c)
+	if want := "0.0.0-20240301123045-" + first[:12]; pseudo != want {
+		t.Fatalf("PseudoVersion = %s, want %s", pseudo, want)
+	}
+	if hash, ok := ParsePseudoVersion(pseudo); !ok || hash != first[:12] {
+		t.Fatalf("ParsePseudoVersion(%s) = %s, %v", pseudo, hash, ok)
+	}
+
+	// main 이 움직여도 커밋이나 pseudo-version 으로 고정한 ref 는 처음 커밋을 가리킵니다.
+	second := repo.commit("second", time.Date(2024, 4, 2, 8, 0, 0, 0, time.UTC))
+	for _, rev := range []string{first, first[:7], pseudo, "v1.0.0"} {
+		c, err := Resolve(cache, Ref{Repo: repo.url(), Rev: rev})
+		if err != nil {
+			t.Fatalf("Resolve(%s): %v", rev, err)
+		}
+		if c.Hash != first {
+			t.Errorf("Resolve(%s) = %s, want %s", rev, c.Hash, first)
+		}
+	}
+	c, err = Resolve(cache, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Hash != second {
+		t.Errorf("Resolve(main) after push = %s, want %s", c.Hash, second)
+	}
+
+	// 받아 둔 mirror 에서는 저장소 없이 해석합니다.
+	if err := os.RemoveAll(repo.bare); err != nil {
+		t.Fatal(err)
+	}
+	c, err = ResolveCached(cache, Ref{Repo: repo.url(), Rev: pseudo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Hash != first {
+		t.Errorf("ResolveCached(%s) = %s, want %s", pseudo, c.Hash, first)
+	}
+	if _, err := Resolve(cache, Ref{Repo: repo.url(), Rev: "no-such-branch"}); err == nil {
+		t.Error("Resolve(no-such-branch) succeeded, want error")
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Ref
+	}{
+		{"git+https://github.com/user/repo.git", Ref{Repo: "https://github.com/user/repo.git"}},
+		{"git+https://github.com/user/repo.git@v1.2#egg=repo", Ref{Repo: "https://github.com/user/repo.git", Rev: "v1.2", Fragment: "egg=repo"}},
+		{"git+ssh://git@github.com/user/repo.git@main", Ref{Repo: "ssh://git@github.com/user/repo.git", Rev: "main"}},
+		{"git+file://localhost/tmp/repo.git@abc1234", Ref{Repo: "file:///tmp/repo.git", Rev: "abc1234"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseURL(tt.in)
+		if err != nil {
+			t.Errorf("ParseURL(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseURL(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{
+		"https://github.com/user/repo.git",
+		"git+",
+		"git+--upload-pack=touch /tmp/pwned",
+		"git+https://github.com/user/repo.git@--output=/tmp/x",
+	} {
+		if _, err := ParseURL(bad); err == nil {
+			t.Errorf("ParseURL(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/janghanul090801/pigo/cmd/vcs"
+)
+
+// vcsTarget 은 pigo install 인자 중 git 에서 받는 패키지입니다.
+type vcsTarget struct {
+	Name   string  // #egg 없이 URL 만 받은 경우 설치 후 커밋으로 찾습니다
+	Line   string  // requirements.txt 에 기록할 줄 (사용자가 쓴 rev 그대로)
+	Pinned vcs.Ref // 커밋으로 고정한 ref
+}
+
+// isVCSArg 는 arg 가 git URL 이나 name@rev 처럼 인덱스가 아닌 곳을 가리키는지 확인합니다.
+func isVCSArg(arg string) bool {
+	if vcs.IsURL(arg) {
+		return true
+	}
+	req, ok := modfile.ParseRequirementLine(arg)
+	return ok && req.URL != ""
+}
+
+// resolveVCSArgs 는 pip install 인자 중 git 의존성의 rev 를 커밋 해시로 고정합니다.
+//
+//	git+https://host/repo.git@main#egg=pkg  -> git+https://host/repo.git@<commit>#egg=pkg
+//	pkg @ git+https://host/repo.git@v1.0    -> pkg @ git+https://host/repo.git@<commit>
+//	pkg@<rev 또는 pseudo-version>            -> requirements.txt 나 pigo.lock 에 있는 저장소의 <commit>
+func resolveVCSArgs(args []string, reqs []dist.Requirement, lock *modfile.LockFile) ([]string, []vcsTarget, error) {
+	var result []string
+	var targets []vcsTarget
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			result = append(result, arg)
+			continue
+		}
+		if vcs.IsURL(arg) {
+			ref, err := vcs.ParseURL(arg)
+			if err != nil {
+				return nil, nil, err
+			}
+			pinned, err := pinRef(ref)
+			if err != nil {
+				return nil, nil, err
+			}
+			result = append(result, pipRef(pinned).String())
+			targets = append(targets, vcsTarget{Name: ref.Egg(), Line: arg, Pinned: pinned})
+			continue
+		}
+
+		req, ok := modfile.ParseRequirementLine(arg)
+		if !ok || req.URL == "" {
+			result = append(result, arg)
+			continue
+		}
+		var ref vcs.Ref
+		switch {
+		case vcs.IsURL(req.URL):
+			parsed, err := vcs.ParseURL(req.URL)
+			if err != nil {
+				return nil, nil, err
+			}
+			ref = parsed
+		case !strings.Contains(req.URL, "://"):
+			// pkg@<rev>: 저장소는 이미 기록된 곳에서 찾습니다.
+			source, ok := vcsSource(req.Name, reqs, lock)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s: no git repository known for %s; install it with %s @ git+<url> first", arg, req.Name, req.Name)
+			}
+			ref = source
+			ref.Rev = req.URL
+		default:
+			result = append(result, arg)
+			continue
+		}
+
+		pinned, err := pinRef(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := vcs.ParsePseudoVersion(ref.Rev); ok {
+			// pseudo-version 은 pip 이 모르므로 requirements.txt 에는 커밋을 적습니다.
+			ref.Rev = pinned.Rev
+		}
+		line := dist.Requirement{Name: req.Name, Extras: req.Extras, URL: ref.String(), Marker: req.Marker}
+		result = append(result, dist.Requirement{Name: req.Name, Extras: req.Extras, URL: pipRef(pinned).RequirementURL()}.String())
+		targets = append(targets, vcsTarget{Name: req.Name, Line: line.String(), Pinned: pinned})
+	}
+	return result, targets, nil
+}
+
+// pinRef 는 ref 의 rev 를 커밋 해시로 바꾼 ref 를 돌려줍니다.
+// offline 이면 캐시의 mirror 에서만 찾습니다.
+func pinRef(ref vcs.Ref) (vcs.Ref, error) {
+	resolve := vcs.Resolve
+	if offlineMode() {
+		resolve = vcs.ResolveCached
+	}
+	commit, err := resolve(pigoCacheDir(), ref)
+	if err != nil {
+		return vcs.Ref{}, err
+	}
+	ref.Rev = commit.Hash
+	return ref, nil
+}
+
+// staleVCSPackages 는 pins 와 같은 저장소에서 다른 커밋으로 설치된 패키지를 찾습니다.
+// pip 은 이름과 버전이 같으면 다시 설치하지 않으므로 먼저 지워야 합니다.
+func staleVCSPackages(pins []vcs.Ref) ([]string, error) {
+	if len(pins) == 0 {
+		return nil, nil
+	}
+	dists, err := dist.LoadVenv(venvDir())
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, d := range dists {
+		installed, ok := installedVCSRef(d)
+		if !ok {
+			continue
+		}
+		for _, pin := range pins {
+			if pin.Repo == installed.Repo && pin.Rev != installed.Rev {
+				stale = append(stale, d.Name)
+				break
+			}
+		}
+	}
+	return stale, nil
+}
+
+// vcsSource 는 name 의 git 저장소를 requirements.txt, pigo.lock 순서로 찾습니다.
+func vcsSource(name string, reqs []dist.Requirement, lock *modfile.LockFile) (vcs.Ref, bool) {
+	for _, req := range reqs {
+		if dist.NormalizeName(req.Name) == dist.NormalizeName(name) && vcs.IsURL(req.URL) {
+			if ref, err := vcs.ParseURL(req.URL); err == nil {
+				return ref, true
+			}
+		}
+	}
+	if p, ok := lock.Find(name); ok && vcs.IsURL(p.Source) {
+		if ref, err := vcs.ParseURL(p.Source); err == nil {
+			return ref, true
+		}
+	}
+	return vcs.Ref{}, false
+}
+
+// lockedCommit 는 pigo.lock 에서 ref 에 해당하는 커밋을 찾습니다.
+// ref 의 rev 가 커밋(또는 pseudo-version)이면 lock 의 커밋과 일치할 때만 씁니다.
+func lockedCommit(lock *modfile.LockFile, name string, ref vcs.Ref) (string, bool) {
+	want, isCommit := vcs.CommitPrefix(ref.Rev)
+	for _, p := range lock.Packages {
+		if name != "" && dist.NormalizeName(p.Name) != dist.NormalizeName(name) {
+			continue
+		}
+		locked, err := vcs.ParseURL(p.Source)
+		if err != nil || locked.Repo != ref.Repo {
+			continue
+		}
+		if isCommit && !strings.HasPrefix(locked.Rev, want) {
+			continue
+		}
+		return locked.Rev, true
+	}
+	return "", false
+}
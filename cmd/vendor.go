@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/janghanul090801/pigo/cmd/vcs"
+	"github.com/spf13/cobra"
+)
+
+func vendorDir() string {
+	return projectPath(modfile.VendorDir)
+}
+
+func vendorManifestPath() string {
+	return filepath.Join(vendorDir(), modfile.VendorManifestName)
+}
+
+// runVendor 는 pigo.lock 의 모든 패키지를 wheel 로 만들어 vendor/ 에 모으고
+// vendor/modules.txt 를 씁니다. vendor/ 는 매번 새로 만들며, 중간에 실패하면
+// 기존 vendor/ 를 그대로 둡니다.
+func runVendor() error {
+	if _, err := os.Stat(lockPath()); os.IsNotExist(err) {
+		if err := writeLock(); err != nil {
+			return err
+		}
+	}
+	lock, err := readLock()
+	if err != nil {
+		return err
+	}
+	if len(lock.Packages) == 0 {
+		return fmt.Errorf("no packages in %s to vendor", modfile.LockFileName)
+	}
+	replaced, err := replacements(projectRoot())
+	if err != nil {
+		return err
+	}
+
+	dir := vendorDir() + ".tmp"
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := &modfile.VendorManifest{}
+	for _, p := range lock.Packages {
+		spec := p.Name + "==" + p.Version
+		if path, ok := replaced[dist.NormalizeName(p.Name)]; ok {
+			spec = path
+		} else if ref, err := vcs.ParseURL(p.Source); err == nil {
+			spec = pipRef(ref).String()
+		} else if p.Source != "" {
+			spec = p.Source
+		}
+		file, err := pipWheel(dir, spec)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+		sum, err := fileSHA256(filepath.Join(dir, file))
+		if err != nil {
+			return err
+		}
+		manifest.Modules = append(manifest.Modules, modfile.VendoredModule{LockedPackage: p, File: file, SHA256: sum})
+	}
+	if err := manifest.Write(filepath.Join(dir, modfile.VendorManifestName)); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(vendorDir()); err != nil {
+		return err
+	}
+	if err := os.Rename(dir, vendorDir()); err != nil {
+		return err
+	}
+	fmt.Printf("Vendored %d packages into %s\n", len(manifest.Modules), displayPath(vendorDir()))
+	return nil
+}
+
+// pipWheel 은 spec 하나를 (의존성 없이) wheel 로 만들어 dir 에 넣고 파일 이름을 돌려줍니다.
+// 인덱스에 wheel 이 있으면 받기만 하고, sdist 나 git 저장소는 빌드합니다.
+func pipWheel(dir, spec string) (string, error) {
+	before, err := listDir(dir)
+	if err != nil {
+		return "", err
+	}
+	args := []string{"wheel", "--no-deps", "--wheel-dir", dir}
+	if offlineMode() {
+		args = append(args, offlinePipArgs()...)
+	}
+	cmd := exec.Command(pipPath(), append(args, spec)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if offlineMode() {
+		cmd.Env = offlineEnv()
+	}
+	if err := runProcess(context.Background(), cmd); err != nil {
+		return "", err
+	}
+	after, err := listDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for name := range after {
+		if !before[name] && strings.HasSuffix(name, ".whl") {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("pip wheel did not produce a wheel for %s", spec)
+}
+
+func listDir(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, e := range entries {
+		names[e.Name()] = true
+	}
+	return names, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyVendor 는 vendor/modules.txt 의 파일이 모두 있고 해시가 맞는지,
+// 그리고 pigo.lock 과 같은 패키지/버전인지 확인합니다.
+func verifyVendor() (*modfile.VendorManifest, error) {
+	manifest, err := modfile.ReadVendorManifest(vendorManifestPath())
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s not found; run 'pigo vendor' first", displayPath(vendorManifestPath()))
+	} else if err != nil {
+		return nil, err
+	}
+	lock, err := readLock()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, mod := range manifest.Modules {
+		path := filepath.Join(vendorDir(), mod.File)
+		sum, err := fileSHA256(path)
+		if os.IsNotExist(err) {
+			problems = append(problems, fmt.Sprintf("%s: %s is missing", mod.Name, displayPath(path)))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if sum != mod.SHA256 {
+			problems = append(problems, fmt.Sprintf("%s: %s checksum mismatch\n\t\twant sha256=%s\n\t\tgot  sha256=%s", mod.Name, displayPath(path), mod.SHA256, sum))
+		}
+		if locked, ok := lock.Find(mod.Name); !ok {
+			problems = append(problems, fmt.Sprintf("%s %s: vendored but not in %s", mod.Name, mod.Version, modfile.LockFileName))
+		} else if locked.Version != mod.Version || locked.Source != mod.Source {
+			problems = append(problems, fmt.Sprintf("%s: %s has %s, vendor/%s has %s", mod.Name, modfile.LockFileName, locked.Version, modfile.VendorManifestName, mod.Version))
+		}
+	}
+	for _, p := range lock.Packages {
+		if _, ok := manifest.Find(p.Name); !ok {
+			problems = append(problems, fmt.Sprintf("%s %s: in %s but not vendored", p.Name, p.Version, modfile.LockFileName))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("inconsistent vendoring in %s:\n\t%s\n\n\tTo sync the vendor directory, run:\n\t\tpigo vendor",
+			displayPath(vendorDir()), strings.Join(problems, "\n\t"))
+	}
+	return manifest, nil
+}
+
+// installVendored 는 vendor/ 에서만 설치합니다. (pip --no-index --find-links vendor)
+// 패키지를 지정하지 않으면 modules.txt 의 모든 wheel 을 설치합니다.
+func installVendored(ctx context.Context, args []string) error {
+	manifest, err := verifyVendor()
+	if err != nil {
+		return err
+	}
+	installArgs := []string{"install", "--no-index", "--find-links", vendorDir()}
+	installArgs = append(installArgs, args...)
+	if !hasPackageArgs(args) {
+		installArgs = append(installArgs, "--no-deps")
+		for _, mod := range manifest.Modules {
+			installArgs = append(installArgs, filepath.Join(vendorDir(), mod.File))
+		}
+	}
+	cmd := exec.Command(pipPath(), installArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	// vendor 에서만 설치하므로 항상 네트워크를 막습니다.
+	cmd.Env = offlineEnv()
+	return runProcess(ctx, cmd)
+}
+
+var vendorCmd = &cobra.Command{
+	Use:   "vendor",
+	Short: "Copy all locked packages into ./vendor",
+	Long: `Builds or downloads a wheel for every package in pigo.lock into ./vendor and
+writes vendor/modules.txt with the version and sha256 of each file.
+
+'pigo install -mod=vendor' installs only from ./vendor, without using the network,
+after checking that the files match vendor/modules.txt and pigo.lock.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runVendor(); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(vendorCmd)
+}
@@ -0,0 +1,164 @@
+package venv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Options 는 가상환경을 만들 때 필요한 정보입니다.
+type Options struct {
+	Interpreter string // 기반 python 실행 파일
+	Version     string // 3.12.1
+	Prompt      string // activate 후 프롬프트에 표시할 이름
+}
+
+// BinDir 는 실행 파일이 들어가는 디렉토리 이름입니다. (linux: bin, windows: Scripts)
+func BinDir() string {
+	if runtime.GOOS == "windows" {
+		return "Scripts"
+	}
+	return "bin"
+}
+
+// SitePackagesDir 는 venv 안의 site-packages 경로입니다.
+func SitePackagesDir(dir, version string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(dir, "Lib", "site-packages")
+	}
+	return filepath.Join(dir, "lib", "python"+shortVersion(version), "site-packages")
+}
+
+// Python 은 venv 안의 python 실행 파일 경로입니다.
+func Python(dir string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(dir, "Scripts", "python.exe")
+	}
+	return filepath.Join(dir, "bin", "python")
+}
+
+func shortVersion(version string) string {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// Create 는 python -m venv 없이 가상환경을 만듭니다.
+// pyvenv.cfg 를 쓰고, interpreter 를 symlink (windows 는 복사) 하고,
+// site-packages 와 bash/zsh/fish 용 activate 스크립트를 만듭니다.
+func Create(dir string, opts Options) error {
+	if opts.Version == "" {
+		return fmt.Errorf("python version is required to create %s", dir)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	// pyenv 처럼 symlink 로 된 interpreter 는 실제 경로를 기준으로 home 을 잡아야
+	// python 이 표준 라이브러리를 찾을 수 있습니다.
+	executable, err := filepath.EvalSymlinks(opts.Interpreter)
+	if err != nil {
+		return err
+	}
+	executable, err = filepath.Abs(executable)
+	if err != nil {
+		return err
+	}
+	if opts.Prompt == "" {
+		opts.Prompt = filepath.Base(filepath.Dir(absDir))
+	}
+
+	binDir := filepath.Join(absDir, BinDir())
+	for _, d := range []string{binDir, filepath.Join(absDir, "include"), SitePackagesDir(absDir, opts.Version)} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return err
+		}
+	}
+	if runtime.GOOS == "linux" && strings.HasSuffix(runtime.GOARCH, "64") {
+		// python -m venv 와 같이 lib64 -> lib 링크를 만듭니다.
+		lib64 := filepath.Join(absDir, "lib64")
+		if _, err := os.Lstat(lib64); os.IsNotExist(err) {
+			if err := os.Symlink("lib", lib64); err != nil {
+				return err
+			}
+		}
+	}
+
+	cfg := fmt.Sprintf("home = %s\ninclude-system-site-packages = false\nversion = %s\nexecutable = %s\nprompt = '%s'\ncommand = pigo init\n",
+		filepath.Dir(executable), opts.Version, executable, opts.Prompt)
+	if err := os.WriteFile(filepath.Join(absDir, "pyvenv.cfg"), []byte(cfg), 0644); err != nil {
+		return err
+	}
+
+	if err := linkInterpreter(binDir, executable, opts.Version); err != nil {
+		return err
+	}
+	return writeActivateScripts(absDir, opts.Prompt)
+}
+
+// linkInterpreter 는 bin/python, python3, python3.X 를 만듭니다.
+func linkInterpreter(binDir, executable, version string) error {
+	if runtime.GOOS == "windows" {
+		// windows 는 symlink 권한이 없을 수 있으므로 python.exe 와 dll 을 복사합니다.
+		base := filepath.Dir(executable)
+		entries, err := os.ReadDir(base)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			name := strings.ToLower(e.Name())
+			if name == "python.exe" || name == "pythonw.exe" || strings.HasSuffix(name, ".dll") {
+				if err := copyFile(filepath.Join(base, e.Name()), filepath.Join(binDir, e.Name())); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	python := filepath.Join(binDir, "python")
+	if err := replaceSymlink(executable, python); err != nil {
+		return err
+	}
+	for _, name := range []string{"python3", "python" + shortVersion(version)} {
+		if err := replaceSymlink("python", filepath.Join(binDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replaceSymlink(target, link string) error {
+	if _, err := os.Lstat(link); err == nil {
+		if err := os.Remove(link); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(target, link)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
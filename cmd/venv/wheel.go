@@ -0,0 +1,269 @@
+package venv
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+)
+
+const scriptTemplate = `# -*- coding: utf-8 -*-
+import re
+import sys
+from %s import %s
+if __name__ == "__main__":
+    sys.argv[0] = re.sub(r"(-script\.pyw|\.exe)?$", "", sys.argv[0])
+    sys.exit(%s())
+`
+
+var versionedScriptRe = regexp.MustCompile(`^(pip|easy_install-)\d+\.\d+$`)
+
+// InstallWheel 은 pip 없이 wheel 파일을 venv 에 설치합니다.
+// .data/ 디렉토리를 나눠 풀고, console_scripts 실행 파일과 INSTALLER, RECORD 를 씁니다.
+func InstallWheel(dir, version, wheel string) error {
+	zr, err := zip.OpenReader(wheel)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	site := SitePackagesDir(absDir, version)
+	binDir := filepath.Join(absDir, BinDir())
+
+	var distInfo string
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, ".dist-info/WHEEL") && strings.Count(f.Name, "/") == 1 {
+			distInfo = strings.TrimSuffix(f.Name, "/WHEEL")
+		}
+	}
+	if distInfo == "" {
+		return fmt.Errorf("%s: not a wheel (no .dist-info/WHEEL)", wheel)
+	}
+	dataDir := strings.TrimSuffix(distInfo, ".dist-info") + ".data"
+
+	w := &recordWriter{site: site}
+	python := Python(absDir)
+	var entryPoints []byte
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		base, name := site, f.Name
+		isScript := false
+		if rest, ok := strings.CutPrefix(f.Name, dataDir+"/"); ok {
+			scheme, sub, _ := strings.Cut(rest, "/")
+			name = sub
+			switch scheme {
+			case "scripts":
+				base, isScript = binDir, true
+			case "purelib", "platlib":
+				base = site
+			case "headers":
+				base = filepath.Join(absDir, "include", "site", "python"+shortVersion(version))
+			case "data":
+				base = absDir
+			default:
+				return fmt.Errorf("%s: unknown data scheme %q", wheel, scheme)
+			}
+		}
+		target, err := joinWithin(base, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", wheel, err)
+		}
+		if f.Name == distInfo+"/RECORD" {
+			// RECORD 는 설치 경로 기준으로 새로 씁니다.
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(0644)
+		if f.Mode()&0111 != 0 {
+			mode = 0755
+		}
+		if isScript {
+			// #!python 은 venv 의 python 으로 바꿉니다.
+			if rest, ok := bytes.CutPrefix(data, []byte("#!python")); ok {
+				data = append([]byte("#!"+python), rest...)
+			}
+			mode = 0755
+		}
+		if f.Name == distInfo+"/entry_points.txt" {
+			entryPoints = data
+		}
+		if err := w.write(target, data, mode); err != nil {
+			return err
+		}
+	}
+
+	for _, ep := range dist.ParseEntryPoints(string(entryPoints)) {
+		if ep.Group != "console_scripts" && ep.Group != "gui_scripts" {
+			continue
+		}
+		// pip 과 같이 pip3.11 같은 이름은 현재 python 버전으로 바꿉니다.
+		if m := versionedScriptRe.FindStringSubmatch(ep.Name); m != nil {
+			ep.Name = m[1] + shortVersion(version)
+		}
+		if err := writeEntryPointScript(w, binDir, python, ep); err != nil {
+			return err
+		}
+	}
+
+	distInfoDir := filepath.Join(site, filepath.FromSlash(distInfo))
+	if err := w.write(filepath.Join(distInfoDir, "INSTALLER"), []byte("pigo\n"), 0644); err != nil {
+		return err
+	}
+	return w.writeRecord(filepath.Join(distInfoDir, "RECORD"))
+}
+
+// joinWithin 은 wheel 안의 경로 name 을 base 아래의 경로로 바꿉니다.
+// ../ 나 절대경로로 base 밖을 가리키면 에러입니다.
+func joinWithin(base, name string) (string, error) {
+	base = filepath.Clean(base)
+	path := filepath.Join(base, filepath.FromSlash(name))
+	if filepath.IsAbs(filepath.FromSlash(name)) || filepath.VolumeName(name) != "" ||
+		!strings.HasPrefix(path, base+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal path in wheel: %s", name)
+	}
+	return path, nil
+}
+
+// writeEntryPointScript 는 "module:attr" 엔트리 포인트를 실행하는 스크립트를 만듭니다.
+func writeEntryPointScript(w *recordWriter, binDir, python string, ep dist.EntryPoint) error {
+	value := strings.TrimSpace(strings.Split(ep.Value, "[")[0])
+	module, attr, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("invalid entry point %s = %s", ep.Name, ep.Value)
+	}
+	module, attr = strings.TrimSpace(module), strings.TrimSpace(attr)
+	importName := strings.Split(attr, ".")[0]
+	body := fmt.Sprintf(scriptTemplate, module, importName, attr)
+	if strings.ContainsAny(ep.Name, `/\`) || ep.Name == ".." {
+		return fmt.Errorf("invalid entry point name %q", ep.Name)
+	}
+
+	if runtime.GOOS == "windows" {
+		// .exe 런처 대신 python 으로 스크립트를 실행하는 .cmd 를 만듭니다.
+		script := filepath.Join(binDir, ep.Name+"-script.py")
+		if err := w.write(script, []byte(body), 0644); err != nil {
+			return err
+		}
+		launcher := fmt.Sprintf("@\"%%~dp0python.exe\" \"%%~dp0%s-script.py\" %%*\r\n", ep.Name)
+		return w.write(filepath.Join(binDir, ep.Name+".cmd"), []byte(launcher), 0644)
+	}
+	return w.write(filepath.Join(binDir, ep.Name), []byte("#!"+python+"\n"+body), 0755)
+}
+
+// recordWriter 는 파일을 쓰면서 RECORD 항목(경로, sha256, 크기)을 모읍니다.
+type recordWriter struct {
+	site    string
+	entries []string
+}
+
+func (w *recordWriter) write(path string, data []byte, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	w.entries = append(w.entries, fmt.Sprintf("%s,sha256=%s,%d",
+		w.rel(path), base64.RawURLEncoding.EncodeToString(sum[:]), len(data)))
+	return nil
+}
+
+func (w *recordWriter) rel(path string) string {
+	rel, err := filepath.Rel(w.site, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	if strings.ContainsAny(rel, ",\"") {
+		rel = `"` + strings.ReplaceAll(rel, `"`, `""`) + `"`
+	}
+	return rel
+}
+
+func (w *recordWriter) writeRecord(path string) error {
+	entries := append(w.entries, w.rel(path)+",,")
+	sort.Strings(entries)
+	return os.WriteFile(path, []byte(strings.Join(entries, "\n")+"\n"), 0644)
+}
+
+// FindPipWheel 은 venv 에 설치할 pip wheel 을 찾습니다.
+// interpreter 의 ensurepip 번들이나 /usr/share/python-wheels 를 먼저 보고
+// (그 python 에서 동작하는 pip 이므로) cacheDir 에 복사해 둡니다.
+// 번들을 찾지 못하면 cacheDir 에 있는 wheel 을 씁니다.
+func FindPipWheel(cacheDir, interpreter string) (string, error) {
+	var patterns []string
+	if real, err := filepath.EvalSymlinks(interpreter); err == nil {
+		prefix := filepath.Dir(filepath.Dir(real))
+		patterns = append(patterns,
+			filepath.Join(prefix, "lib", "python*", "ensurepip", "_bundled", "pip-*.whl"),
+			filepath.Join(filepath.Dir(real), "Lib", "ensurepip", "_bundled", "pip-*.whl"),
+		)
+	}
+	patterns = append(patterns, "/usr/share/python-wheels/pip-*.whl")
+
+	for _, pattern := range patterns {
+		wheel := newestWheel(pattern)
+		if wheel == "" {
+			continue
+		}
+		cached := filepath.Join(cacheDir, filepath.Base(wheel))
+		if _, err := os.Stat(cached); err == nil {
+			return cached, nil
+		}
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			return "", err
+		}
+		if err := copyFile(wheel, cached); err != nil {
+			return "", err
+		}
+		return cached, nil
+	}
+
+	if wheel := newestWheel(filepath.Join(cacheDir, "pip-*.whl")); wheel != "" {
+		return wheel, nil
+	}
+	return "", fmt.Errorf("no pip wheel found (put a pip-*.whl into %s)", cacheDir)
+}
+
+// newestWheel 은 pattern 에 맞는 wheel 중 버전이 가장 높은 것을 돌려줍니다.
+func newestWheel(pattern string) string {
+	matches, _ := filepath.Glob(pattern)
+	best, bestVersion := "", ""
+	for _, m := range matches {
+		parts := strings.Split(filepath.Base(m), "-")
+		if len(parts) < 2 {
+			continue
+		}
+		if best == "" || dist.CompareVersions(parts[1], bestVersion) > 0 {
+			best, bestVersion = m, parts[1]
+		}
+	}
+	return best
+}
@@ -0,0 +1,89 @@
+package venv
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeWheel 은 files (이름 -> 내용) 로 테스트용 wheel 을 만듭니다.
+func writeWheel(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "demo-1.0-py3-none-any.whl")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInstallWheel(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "venv")
+	wheel := writeWheel(t, map[string]string{
+		"demo/__init__.py":                    "",
+		"demo-1.0.data/scripts/tool":          "#!python\nprint(1)\n",
+		"demo-1.0.dist-info/WHEEL":            "Wheel-Version: 1.0\n",
+		"demo-1.0.dist-info/entry_points.txt": "[console_scripts]\ndemo = demo:main\n",
+	})
+	if err := InstallWheel(dir, "3.12.1", wheel); err != nil {
+		t.Fatal(err)
+	}
+	site := SitePackagesDir(dir, "3.12.1")
+	for _, path := range []string{
+		filepath.Join(site, "demo", "__init__.py"),
+		filepath.Join(site, "demo-1.0.dist-info", "RECORD"),
+		filepath.Join(dir, BinDir(), "tool"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s not installed: %v", path, err)
+		}
+	}
+}
+
+func TestInstallWheelRejectsTraversal(t *testing.T) {
+	tests := map[string]map[string]string{
+		"parent":        {"../../evil": "x"},
+		"nested parent": {"demo/../../../evil": "x"},
+		"absolute":      {"/tmp/evil": "x"},
+		"data scheme":   {"demo-1.0.data/scripts/../../../evil": "x"},
+		"data dir":      {"demo-1.0.data/data/../../evil": "x"},
+		"entry point":   {"demo-1.0.dist-info/entry_points.txt": "[console_scripts]\n../../evil = demo:main\n"},
+	}
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(root, "a", "b", "venv")
+			files["demo-1.0.dist-info/WHEEL"] = "Wheel-Version: 1.0\n"
+			err := InstallWheel(dir, "3.12.1", writeWheel(t, files))
+			if err == nil {
+				t.Fatal("InstallWheel succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), "evil") {
+				t.Errorf("error = %v, want it to name the bad path", err)
+			}
+			matches, _ := filepath.Glob(filepath.Join(root, "**", "evil"))
+			more, _ := filepath.Glob(filepath.Join(root, "evil"))
+			if len(matches)+len(more) > 0 {
+				t.Errorf("file written outside the venv: %v", append(matches, more...))
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+const (
+	// watchInterval 은 파일 변경을 확인하는 간격입니다.
+	watchInterval = 500 * time.Millisecond
+	// watchDebounce 는 변경을 발견한 뒤 재시작하기 전에 기다리는 시간입니다.
+	// 에디터가 여러 파일을 연달아 저장해도 한 번만 재시작합니다.
+	watchDebounce = 300 * time.Millisecond
+)
+
+// fileStamp 는 변경 여부를 비교할 파일 정보입니다.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchSnapshot 은 root 아래 .py 파일의 상태입니다.
+// 가상환경, skipDirs, .gitignore 에 맞는 경로는 건너뜁니다.
+func watchSnapshot(root string, rules *ignoreRules) map[string]fileStamp {
+	files := make(map[string]fileStamp)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if info.IsDir() {
+			if skipDirs[info.Name()] || rules.match(rel, true) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "pyvenv.cfg")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".py" && !rules.match(rel, false) {
+			files[path] = fileStamp{info.ModTime(), info.Size()}
+		}
+		return nil
+	})
+	return files
+}
+
+// changedFile 은 두 snapshot 에서 달라진 파일 하나를 돌려줍니다. 없으면 "" 입니다.
+func changedFile(old, cur map[string]fileStamp) string {
+	for path, s := range cur {
+		if o, ok := old[path]; !ok || o != s {
+			return path
+		}
+	}
+	for path := range old {
+		if _, ok := cur[path]; !ok {
+			return path
+		}
+	}
+	return ""
+}
+
+// runWatch 는 newCmd 로 만든 프로세스를 실행하고, 프로젝트의 .py 파일이 바뀌면
+// 이전 프로세스를 끝내고(SIGTERM, killGrace 뒤 SIGKILL) 다시 실행합니다.
+// 프로세스가 스스로 끝나면 다음 변경까지 기다립니다. pigo 가 SIGINT/SIGTERM 을 받으면 종료합니다.
+func runWatch(newCmd func() *exec.Cmd) error {
+	root := projectRoot()
+	rules := readIgnoreRules(root)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	var cancel context.CancelFunc
+	var exited chan error
+	start := func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		exited = make(chan error, 1)
+		cmd := newCmd()
+		go func(done chan error) { done <- runProcess(ctx, cmd) }(exited)
+	}
+	stop := func() {
+		if exited == nil {
+			return
+		}
+		cancel()
+		<-exited
+		exited = nil
+	}
+
+	snapshot := watchSnapshot(root, rules)
+	start()
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case sig := <-sigs:
+			stop()
+			code := 1
+			if s, ok := sig.(syscall.Signal); ok {
+				code = 128 + int(s)
+			}
+			return &exitError{Code: code}
+		case err := <-exited:
+			exited = nil
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[pigo] process exited: %v; waiting for changes...\n", err)
+			} else {
+				fmt.Fprintln(os.Stderr, "[pigo] process exited; waiting for changes...")
+			}
+		case <-ticker.C:
+			cur := watchSnapshot(root, rules)
+			changed := changedFile(snapshot, cur)
+			if changed == "" {
+				continue
+			}
+			time.Sleep(watchDebounce)
+			snapshot = watchSnapshot(root, rules)
+			fmt.Fprintf(os.Stderr, "[pigo] %s changed; restarting...\n", displayPath(changed))
+			stop()
+			start()
+		}
+	}
+}
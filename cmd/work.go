@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/janghanul090801/pigo/cmd/dist"
+	"github.com/janghanul090801/pigo/cmd/modfile"
+	"github.com/spf13/cobra"
+)
+
+// workspacePattern 은 go 의 ./... 처럼 workspace 의 모든 프로젝트를 뜻합니다.
+const workspacePattern = "./..."
+
+// findWorkspace 는 현재 디렉토리에서 위로 올라가며 pigo.work 를 찾습니다.
+func findWorkspace() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, modfile.WorkFileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// workspaceMembers 는 pigo.work 에 나열된 프로젝트 디렉토리들의 절대경로입니다.
+func workspaceMembers() ([]string, error) {
+	path, ok := findWorkspace()
+	if !ok {
+		return nil, fmt.Errorf("%s not found (run 'pigo work init')", modfile.WorkFileName)
+	}
+	work, err := modfile.ReadWork(path)
+	if err != nil {
+		return nil, err
+	}
+	var members []string
+	for _, use := range work.Use {
+		members = append(members, filepath.Join(filepath.Dir(path), filepath.FromSlash(use)))
+	}
+	return members, nil
+}
+
+// forEachMember 는 workspace 의 각 프로젝트로 이동해서 fn 을 실행합니다. (pigo -C member ... 와 같습니다)
+// 하나가 실패해도 나머지를 계속 실행하고, 실패한 프로젝트를 모아서 돌려줍니다.
+func forEachMember(fn func(member string) error) error {
+	members, err := workspaceMembers()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defer chdirProject(cwd)
+
+	var failed []string
+	for _, member := range members {
+		fmt.Printf("# %s\n", displayPathFrom(cwd, member))
+		if err := chdirProject(member); err != nil {
+			return err
+		}
+		if err := fn(member); err != nil {
+			log.Printf("%s: %v", displayPathFrom(cwd, member), err)
+			failed = append(failed, displayPathFrom(cwd, member))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed in %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func displayPathFrom(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// cutWorkspacePattern 은 플래그 파싱을 하지 않는 명령의 인자에서 ./... 를 빼냅니다.
+func cutWorkspacePattern(args []string) ([]string, bool) {
+	var rest []string
+	found := false
+	for _, arg := range args {
+		if arg == workspacePattern {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// pinnedVersion 은 name==1.2.3 처럼 정확히 고정된 버전을 돌려줍니다.
+func pinnedVersion(req dist.Requirement) (string, bool) {
+	spec, ok := strings.CutPrefix(req.Specifier, "==")
+	if !ok || strings.ContainsAny(spec, ",*") || strings.HasPrefix(spec, "=") {
+		return "", false
+	}
+	return spec, true
+}
+
+// syncWorkspace 는 workspace 전체에서 같은 패키지가 같은 버전으로 고정되도록
+// 각 프로젝트의 requirements.txt 를 맞춥니다. 모든 프로젝트의 범위 조건을 만족하는
+// 후보(고정된 버전, 설치된 버전) 중 가장 높은 버전을 고릅니다.
+// 맞출 수 없는 패키지가 있으면 아무 파일도 바꾸지 않고 실패합니다.
+func syncWorkspace(members []string) error {
+	type memberFile struct {
+		dir  string
+		path string
+		reqs *modfile.Requirements
+	}
+	var files []*memberFile
+	constraints := make(map[string][]string)
+	candidates := make(map[string][]string)
+	pinned := make(map[string]bool)
+
+	for _, member := range members {
+		path := filepath.Join(member, modfile.RequirementsFileName)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		reqs, err := modfile.ReadRequirements(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &memberFile{dir: member, path: path, reqs: reqs})
+
+		for _, req := range reqs.List() {
+			if req.URL != "" {
+				continue
+			}
+			name := dist.NormalizeName(req.Name)
+			if version, ok := pinnedVersion(req); ok {
+				pinned[name] = true
+				candidates[name] = append(candidates[name], version)
+			} else if req.Specifier != "" {
+				constraints[name] = append(constraints[name], req.Specifier)
+			}
+		}
+		if dists, err := dist.LoadVenv(filepath.Join(member, ".venv")); err == nil {
+			for _, d := range dists {
+				name := dist.NormalizeName(d.Name)
+				candidates[name] = append(candidates[name], d.Version)
+			}
+		}
+	}
+
+	chosen := make(map[string]string)
+	var conflicts []string
+	for name := range pinned {
+		best := ""
+		for _, version := range candidates[name] {
+			ok := true
+			for _, spec := range constraints[name] {
+				if !dist.MatchSpecifier(version, spec) {
+					ok = false
+					break
+				}
+			}
+			if ok && (best == "" || dist.CompareVersions(version, best) > 0) {
+				best = version
+			}
+		}
+		if best == "" {
+			conflicts = append(conflicts, fmt.Sprintf("%s: no version satisfies %s", name, strings.Join(constraints[name], " and ")))
+			continue
+		}
+		chosen[name] = best
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("conflicting requirements:\n\t%s", strings.Join(conflicts, "\n\t"))
+	}
+
+	for _, mf := range files {
+		changed := false
+		for _, req := range mf.reqs.List() {
+			version, isPinned := pinnedVersion(req)
+			best, ok := chosen[dist.NormalizeName(req.Name)]
+			if !isPinned || !ok || version == best {
+				continue
+			}
+			fmt.Printf("%s: %s %s => %s\n", displayPath(mf.dir), req.Name, version, best)
+			req.Specifier = "==" + best
+			mf.reqs.Set(req)
+			changed = true
+		}
+		if changed {
+			if err := mf.reqs.Write(mf.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var workCmd = &cobra.Command{
+	Use:   "work",
+	Short: "Manage a workspace of several python projects",
+	Long: `A workspace is a pigo.work file (like go.work) listing the projects that belong to it.
+Commands such as 'pigo tidy ./...' and 'pigo install ./...' run in every listed project.`,
+}
+
+var workInitCmd = &cobra.Command{
+	Use:   "init [dirs...]",
+	Short: "Create pigo.work in the current directory",
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := os.Stat(modfile.WorkFileName); err == nil {
+			log.Fatalf("error: %s already exists", modfile.WorkFileName)
+		}
+		work := &modfile.WorkFile{}
+		for _, dir := range args {
+			work.AddUse(workUsePath(dir))
+		}
+		if err := work.Write(modfile.WorkFileName); err != nil {
+			log.Fatalf("error writing %s: %v", modfile.WorkFileName, err)
+		}
+	},
+}
+
+var workUseCmd = &cobra.Command{
+	Use:   "use <dirs...>",
+	Short: "Add projects to pigo.work",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, ok := findWorkspace()
+		if !ok {
+			log.Fatalf("error: %s not found (run 'pigo work init')", modfile.WorkFileName)
+		}
+		work, err := modfile.ReadWork(path)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		for _, dir := range args {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				log.Fatalf("error: %v", err)
+			}
+			rel, err := filepath.Rel(filepath.Dir(path), abs)
+			if err != nil {
+				log.Fatalf("error: %v", err)
+			}
+			work.AddUse(workUsePath(rel))
+		}
+		if err := work.Write(path); err != nil {
+			log.Fatalf("error writing %s: %v", path, err)
+		}
+	},
+}
+
+// workUsePath 는 use 지시어에 쓸 ./dir 형태의 경로를 만듭니다.
+func workUsePath(dir string) string {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	if !strings.HasPrefix(dir, ".") && !filepath.IsAbs(dir) {
+		dir = "./" + dir
+	}
+	return dir
+}
+
+var workSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Make pinned versions agree across the workspace",
+	Long: `Collects the requirements of every project in pigo.work and rewrites the
+pinned (==) versions so that each package is pinned to the same version everywhere.
+The highest pinned or installed version that satisfies every project's constraints wins.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		members, err := workspaceMembers()
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		if err := syncWorkspace(members); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workCmd)
+	workCmd.AddCommand(workInitCmd)
+	workCmd.AddCommand(workUseCmd)
+	workCmd.AddCommand(workSyncCmd)
+}
@@ -3,6 +3,7 @@ module github.com/janghanul090801/pigo
 go 1.25.2
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/smacker/go-tree-sitter v0.0.0-20240827094217-dd81d9e9be82
 	github.com/spf13/cobra v1.10.2
 )
@@ -14,7 +14,7 @@ func main() {
 	case "windows":
 		_const.PIPPATH = _const.PIPPATHWINDOW
 		_const.PYTHONPATH = _const.PYTHONPATHWINDOW
-	case "linux":
+	default:
 		_const.PIPPATH = _const.PIPPATHLINUX
 		_const.PYTHONPATH = _const.PYTHONPATHLINUX
 	}